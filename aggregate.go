@@ -0,0 +1,175 @@
+package ondatra
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Aggregate is an aggregate function call, Column[T] and any Expr can be its argument
+type Aggregate struct {
+	function  string
+	distinct  bool
+	args      []Expr
+	orderBy   []Expr
+	separator string
+	filter    []Expr
+}
+
+func NewAggregate(function string, args ...Expr) Aggregate {
+	return Aggregate{
+		function: function,
+		args:     args,
+	}
+}
+
+func Count(expr Expr) Aggregate {
+	return NewAggregate("COUNT", expr)
+}
+
+func CountAll() Aggregate {
+	return NewAggregate("COUNT", NewExpr("*"))
+}
+
+func CountDistinct(expr Expr) Aggregate {
+	return NewAggregate("COUNT", expr).Distinct()
+}
+
+func Sum(expr Expr) Aggregate {
+	return NewAggregate("SUM", expr)
+}
+
+func Avg(expr Expr) Aggregate {
+	return NewAggregate("AVG", expr)
+}
+
+func Min(expr Expr) Aggregate {
+	return NewAggregate("MIN", expr)
+}
+
+func Max(expr Expr) Aggregate {
+	return NewAggregate("MAX", expr)
+}
+
+func ArrayAgg(expr Expr) Aggregate {
+	return NewAggregate("ARRAY_AGG", expr)
+}
+
+// StringAgg concatenates values with separator, postgres STRING_AGG
+func StringAgg(expr Expr, separator string) Aggregate {
+	return NewAggregate("STRING_AGG", expr, NewExpr("?", separator))
+}
+
+// GroupConcat concatenates values with separator, mysql GROUP_CONCAT
+func GroupConcat(expr Expr, separator string) Aggregate {
+	a := NewAggregate("GROUP_CONCAT", expr)
+	a.separator = separator
+	return a
+}
+
+func (a Aggregate) Distinct() Aggregate {
+	a.distinct = true
+	return a
+}
+
+// OrderBy set order of aggregated values, e.g. ARRAY_AGG(x ORDER BY y)
+func (a Aggregate) OrderBy(orderBys ...string) Aggregate {
+	for _, orderBy := range orderBys {
+		a.orderBy = append(a.orderBy, NewExpr(orderBy))
+	}
+	return a
+}
+
+func (a Aggregate) OrderByExpr(expr ...Expr) Aggregate {
+	for i := range expr {
+		if expr[i] != nil {
+			a.orderBy = append(a.orderBy, expr[i])
+		}
+	}
+	return a
+}
+
+// Filter aggregates only rows matched by conditions, e.g. COUNT(x) FILTER (WHERE ...)
+func (a Aggregate) Filter(expr ...Expr) Aggregate {
+	for i := range expr {
+		if expr[i] != nil {
+			a.filter = append(a.filter, expr[i])
+		}
+	}
+	return a
+}
+
+// As returns aliased expression for select columns
+func (a Aggregate) As(alias string) Expr {
+	return As(a, alias)
+}
+
+// Over returns aggregate evaluated over window
+func (a Aggregate) Over(w WindowSpec) Expr {
+	return WindowFunc{Expr: a}.Over(w)
+}
+
+// OverName returns aggregate evaluated over window defined by Builder.Window
+func (a Aggregate) OverName(name string) Expr {
+	return WindowFunc{Expr: a}.OverName(name)
+}
+
+func (a Aggregate) ToSQL() (string, []any, error) {
+	var err error
+	var args []any
+	var buffer strings.Builder
+
+	buffer.WriteString(a.function)
+	buffer.WriteString("(")
+	if a.distinct {
+		buffer.WriteString("DISTINCT ")
+	}
+
+	if args, err = writeExprs(a.args, &buffer, ", ", args); err != nil {
+		return "", nil, err
+	}
+
+	if len(a.orderBy) > 0 {
+		buffer.WriteString(" ORDER BY ")
+		if args, err = writeExprs(a.orderBy, &buffer, ", ", args); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if a.separator != "" {
+		buffer.WriteString(" SEPARATOR ")
+		buffer.WriteString(DialectMySQL.Literal(a.separator))
+	}
+	buffer.WriteString(")")
+
+	if len(a.filter) > 0 {
+		buffer.WriteString(" FILTER (WHERE ")
+		if args, err = writeExprs(a.filter, &buffer, " AND ", args); err != nil {
+			return "", nil, err
+		}
+		buffer.WriteString(")")
+	}
+
+	return buffer.String(), args, nil
+}
+
+func (a Aggregate) bindSubqueries(bind func(Builder) Builder) Expr {
+	a.args = bindSubqueriesExprs(a.args, bind)
+	a.orderBy = bindSubqueriesExprs(a.orderBy, bind)
+	a.filter = bindSubqueriesExprs(a.filter, bind)
+	return a
+}
+
+// As returns expression with alias, e.g. COUNT(x) AS users
+func As(expr Expr, alias string) Expr {
+	return NewExpr("? AS "+alias, expr)
+}
+
+// Coalesce returns first not null value, values can be Expr or arguments
+func Coalesce(values ...any) Expr {
+	return NewExpr(fmt.Sprintf("COALESCE(%s)", strings.TrimRight(strings.Repeat("?, ", len(values)), ", ")), values...)
+}
+
+// Cast converts expression to the type, e.g. CAST(x AS numeric)
+func Cast(expr Expr, typeName string) Expr {
+	return NewExpr(fmt.Sprintf("CAST(? AS %s)", typeName), expr)
+}
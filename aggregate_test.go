@@ -0,0 +1,74 @@
+package ondatra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	userID := NewColumn[int64]("orders", "user_id")
+	status := NewColumn[string]("orders", "status")
+	total := NewColumn[int64]("orders", "total")
+	name := NewColumn[string]("users", "name")
+
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+	}{
+		{
+			name: "group by columns",
+			builder: NewEmptyBuilder().
+				Select().
+				SelectExpr(
+					status,
+					CountDistinct(userID).As("users"),
+					Count(userID).Filter(total.GT.Value(100)).As("big_orders"),
+					Sum(total).As("total"),
+					As(Coalesce(Avg(total), 0), "avg"),
+				).
+				SelectColumn("? AS min_total", Min(total)).
+				SelectExpr(Max(Cast(total, "numeric")).As("max_total")).
+				From("orders").
+				Where("created_at > ?", "2024-01-01").
+				GroupByExpr(status, NewExpr("date_trunc(?, created_at)", "day")).
+				HavingExpr(NewExpr("? > ?", CountAll(), 5)).
+				OrderByArgs("? DESC", Sum(total)),
+			expectQuery: "SELECT \"orders\".status, COUNT(DISTINCT \"orders\".user_id) AS users, " +
+				"COUNT(\"orders\".user_id) FILTER (WHERE \"orders\".total > ?) AS big_orders, " +
+				"SUM(\"orders\".total) AS total, COALESCE(AVG(\"orders\".total), ?) AS avg, " +
+				"MIN(\"orders\".total) AS min_total, MAX(CAST(\"orders\".total AS numeric)) AS max_total " +
+				"FROM orders WHERE created_at > ? GROUP BY \"orders\".status, date_trunc(?, created_at) " +
+				"HAVING COUNT(*) > ? ORDER BY SUM(\"orders\".total) DESC",
+			expectArgs: []any{int64(100), 0, "2024-01-01", "day", 5},
+		}, {
+			name: "string aggregates",
+			builder: NewEmptyBuilder().
+				Select("id").
+				SelectExpr(
+					StringAgg(name, ", ").OrderBy("name").As("names"),
+					GroupConcat(name, "'; ").Distinct().OrderByExpr(name).As("concat"),
+					ArrayAgg(userID).Filter(status.EQ.Value("paid")).As("ids"),
+					As(Sum(total).Over(NewWindow().PartitionByExpr(userID)), "user_total"),
+				).
+				From("users").
+				GroupBy("id"),
+			expectQuery: "SELECT id, STRING_AGG(\"users\".name, ? ORDER BY name) AS names, " +
+				"GROUP_CONCAT(DISTINCT \"users\".name ORDER BY \"users\".name SEPARATOR '''; ') AS concat, " +
+				"ARRAY_AGG(\"orders\".user_id) FILTER (WHERE \"orders\".status = ?) AS ids, " +
+				"SUM(\"orders\".total) OVER (PARTITION BY \"orders\".user_id) AS user_total " +
+				"FROM users GROUP BY id",
+			expectArgs: []any{", ", "paid"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package ondatra
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BatchQuery is a built statement of Batch with placeholders of the connection
+type BatchQuery struct {
+	SQL  string
+	Args []any
+}
+
+// BatchResult is a result of one statement of Batch, RowsAffected is -1 when the driver does not report it
+type BatchResult struct {
+	RowsAffected int64
+	Err          error
+}
+
+// BatchExecutor sends all statements of batch in one round trip, e.g. pgxbatch adapter of pgx batch.
+// Results must be in order of queries.
+type BatchExecutor interface {
+	ExecBatch(ctx context.Context, queries []BatchQuery) ([]BatchResult, error)
+}
+
+// Batch collects insert, update and delete statements which are executed together
+type Batch struct {
+	builder        Builder
+	statements     []Builder
+	executor       BatchExecutor
+	multiStatement bool
+}
+
+// Batch returns batch of statements executed by connection of the builder
+func (b Builder) Batch(statements ...Builder) Batch {
+	return Batch{builder: b}.Add(statements...)
+}
+
+func (bt Batch) Add(statements ...Builder) Batch {
+	bt.statements = append(bt.statements[:len(bt.statements):len(bt.statements)], statements...)
+	return bt
+}
+
+func (bt Batch) Len() int {
+	return len(bt.statements)
+}
+
+// Executor sets executor which sends statements in one round trip,
+// by default it is the writer connection when it implements BatchExecutor
+func (bt Batch) Executor(executor BatchExecutor) Batch {
+	bt.executor = executor
+	return bt
+}
+
+// MultiStatement sends statements joined by semicolon in one query, the driver must allow it,
+// e.g. multiStatements=true and interpolateParams=true parameters of mysql DSN. MySQL stops at
+// the first failed statement and does not roll back the previous ones unless the batch runs in transaction.
+func (bt Batch) MultiStatement() Batch {
+	bt.multiStatement = true
+	return bt
+}
+
+// Queries returns built statements of the batch
+func (bt Batch) Queries(ctx context.Context) ([]BatchQuery, error) {
+	return bt.queries(ctx, nil)
+}
+
+// queries builds statements, placeholders are left as question marks when placeholderFormat is Question
+func (bt Batch) queries(ctx context.Context, placeholderFormat PlaceholderFormat) ([]BatchQuery, error) {
+	queries := make([]BatchQuery, len(bt.statements))
+	for i, statement := range bt.statements {
+		var err error
+		if statement.writerConn == nil && statement.readerConn == nil {
+			statement = statement.withConnections(bt.builder)
+		}
+		if placeholderFormat != nil {
+			statement.placeholderFormat = placeholderFormat
+		}
+		if statement.writerConn == nil && statement.readerConn == nil {
+			queries[i].SQL, queries[i].Args, err = statement.ToSQLContext(ctx)
+		} else {
+			queries[i].SQL, queries[i].Args, err = statement.ToQueryWithArgsContext(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("batch statement %d: %w", i, err)
+		}
+	}
+	return queries, nil
+}
+
+// ExecContext executes statements in one round trip by executor or multi statement query, otherwise
+// sequentially in one transaction. Results are in order of statements, the error is the first failure.
+// Statements after a failed one have ErrBatchAborted when they are not executed.
+func (bt Batch) ExecContext(ctx context.Context) ([]BatchResult, error) {
+	if len(bt.statements) == 0 {
+		return nil, nil
+	}
+
+	executor := bt.executor
+	if executor == nil {
+		executor, _ = bt.builder.writerConn.(BatchExecutor)
+	}
+
+	var placeholderFormat PlaceholderFormat
+	if executor == nil && bt.multiStatement {
+		placeholderFormat = Question
+	}
+	queries, err := bt.queries(ctx, placeholderFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []BatchResult
+	switch {
+	case executor != nil:
+		results, err = executor.ExecBatch(ctx, queries)
+	case bt.multiStatement:
+		results, err = bt.execMultiStatement(ctx, queries)
+	default:
+		results, err = bt.execSequential(ctx, queries)
+	}
+	if err != nil {
+		return results, err
+	}
+	return results, batchError(results)
+}
+
+// execSequential executes statements one by one in the current transaction or a new one
+func (bt Batch) execSequential(ctx context.Context, queries []BatchQuery) ([]BatchResult, error) {
+	results := make([]BatchResult, len(queries))
+	for i := range results {
+		results[i] = BatchResult{RowsAffected: -1, Err: ErrBatchAborted}
+	}
+
+	err := bt.builder.transaction(ctx, func(tx Builder) error {
+		for i, query := range queries {
+			result, err := tx.writerConn.ExecContext(ctx, query.SQL, query.Args...)
+			if err != nil {
+				results[i].Err = err
+				return batchError(results)
+			}
+			results[i] = BatchResult{RowsAffected: rowsAffected(result)}
+		}
+		return nil
+	})
+	if err != nil && !bt.builder.inTransaction() {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = fmt.Errorf("%w: transaction is rolled back", ErrBatchAborted)
+			}
+		}
+	}
+	return results, err
+}
+
+// execMultiStatement executes statements joined by semicolon with placeholders numbered through the query, rows affected by every statement
+// are known only when the driver result has AllRowsAffected like mysql driver
+func (bt Batch) execMultiStatement(ctx context.Context, queries []BatchQuery) ([]BatchResult, error) {
+	sqls := make([]string, len(queries))
+	var args []any
+	for i, query := range queries {
+		sqls[i] = query.SQL
+		args = append(args, query.Args...)
+	}
+	query := strings.Join(sqls, ";\n")
+	if bt.builder.placeholderFormat != nil {
+		query = bt.builder.placeholderFormat.ReplacePlaceholders(query)
+	} else if bt.builder.writerConn != nil {
+		query = bt.builder.writerConn.Rebind(query)
+	}
+
+	results := make([]BatchResult, len(queries))
+	for i := range results {
+		results[i].RowsAffected = -1
+	}
+
+	all, err := bt.execAllRowsAffected(ctx, query, args)
+	if err != nil {
+		for i := range results {
+			results[i].Err = err
+		}
+		return results, fmt.Errorf("batch: %w", err)
+	}
+	for i := range results {
+		if i < len(all) {
+			results[i].RowsAffected = all[i]
+		}
+	}
+	return results, nil
+}
+
+func (bt Batch) execAllRowsAffected(ctx context.Context, query string, args []any) ([]int64, error) {
+	var db *DB
+	switch conn := bt.builder.writerConn.(type) {
+	case *DB:
+		db = conn
+	case *CachedDB:
+		db = conn.db
+	}
+
+	if db == nil {
+		_, err := bt.builder.writerConn.ExecContext(ctx, query, args...)
+		return nil, err
+	}
+
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var all []int64
+	executed := false
+	err = conn.Raw(func(driverConn any) error {
+		execer, ok := driverConn.(driver.ExecerContext)
+		if !ok {
+			return nil
+		}
+		namedArgs, err := driverArgs(driverConn, args)
+		if err != nil {
+			return err
+		}
+
+		result, err := execer.ExecContext(ctx, query, namedArgs)
+		if errors.Is(err, driver.ErrSkip) {
+			return nil
+		}
+		executed = true
+		if err != nil {
+			return err
+		}
+		if r, ok := result.(interface{ AllRowsAffected() []int64 }); ok {
+			all = r.AllRowsAffected()
+		}
+		return nil
+	})
+	if err != nil || executed {
+		return all, err
+	}
+
+	_, err = conn.ExecContext(ctx, query, args...)
+	return nil, err
+}
+
+// driverArgs converts args by value checker of the driver connection or by the default converter
+func driverArgs(driverConn any, args []any) ([]driver.NamedValue, error) {
+	checker, _ := driverConn.(driver.NamedValueChecker)
+	namedArgs := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		namedArgs[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+		if checker != nil {
+			err := checker.CheckNamedValue(&namedArgs[i])
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, driver.ErrSkip) {
+				return nil, fmt.Errorf("argument %d: %w", i, err)
+			}
+		}
+
+		value, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		namedArgs[i].Value = value
+	}
+	return namedArgs, nil
+}
+
+func rowsAffected(result interface{ RowsAffected() (int64, error) }) int64 {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// batchError returns error of the first failed statement
+func batchError(results []BatchResult) error {
+	for i, result := range results {
+		if result.Err != nil {
+			return fmt.Errorf("batch statement %d: %w", i, result.Err)
+		}
+	}
+	return nil
+}
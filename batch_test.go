@@ -0,0 +1,132 @@
+package ondatra
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeBatchExecutor struct {
+	queries []BatchQuery
+}
+
+func (e *fakeBatchExecutor) ExecBatch(_ context.Context, queries []BatchQuery) ([]BatchResult, error) {
+	e.queries = queries
+	results := make([]BatchResult, len(queries))
+	for i := range results {
+		results[i].RowsAffected = int64(i + 1)
+	}
+	return results, nil
+}
+
+func TestBatch_Queries(t *testing.T) {
+	b := NewEmptyBuilder().PlaceholderFormat(Dollar)
+	batch := b.Batch(
+		b.Insert().Into("users").Columns("name").Values("a"),
+		b.Update().Table("users").Set("name", "b").Where("id = ?", 1),
+	).Add(b.Delete().From("users").Where("id = ?", 2))
+
+	queries, err := batch.Queries(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, batch.Len())
+	assert.Equal(t, []BatchQuery{
+		{SQL: "INSERT INTO users (name) VALUES ($1)", Args: []any{"a"}},
+		{SQL: "UPDATE users SET name = $1 WHERE id = $2", Args: []any{"b", 1}},
+		{SQL: "DELETE FROM users WHERE id = $1", Args: []any{2}},
+	}, queries)
+
+	_, err = b.Batch(b.Insert().Into("users")).Queries(context.Background())
+	assert.ErrorIs(t, err, NotSetValues)
+}
+
+func TestBatch_ExecContext(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sequential in transaction", func(t *testing.T) {
+		b, mock := newMockBuilder(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO users (name) VALUES ($1)").WithArgs("a").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("DELETE FROM users WHERE id = $1").WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		results, err := b.Batch(
+			b.Insert().Into("users").Columns("name").Values("a"),
+			b.Delete().From("users").Where("id = ?", 2),
+		).ExecContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []BatchResult{{RowsAffected: 1}, {RowsAffected: 3}}, results)
+	})
+
+	t.Run("sequential failure", func(t *testing.T) {
+		b, mock := newMockBuilder(t)
+		failed := errors.New("duplicate key")
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO users (name) VALUES ($1)").WithArgs("a").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec("INSERT INTO users (name) VALUES ($1)").WithArgs("b").
+			WillReturnError(failed)
+		mock.ExpectRollback()
+
+		results, err := b.Batch(
+			b.Insert().Into("users").Columns("name").Values("a"),
+			b.Insert().Into("users").Columns("name").Values("b"),
+			b.Insert().Into("users").Columns("name").Values("c"),
+		).ExecContext(ctx)
+		assert.ErrorIs(t, err, failed)
+		assert.EqualError(t, err, "batch statement 1: duplicate key")
+		assert.Len(t, results, 3)
+		assert.ErrorIs(t, results[0].Err, ErrBatchAborted)
+		assert.ErrorIs(t, results[1].Err, failed)
+		assert.ErrorIs(t, results[2].Err, ErrBatchAborted)
+	})
+
+	t.Run("in existing transaction", func(t *testing.T) {
+		b, mock := newMockBuilder(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users SET name = $1").WithArgs("a").
+			WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectCommit()
+
+		err := b.RunInTransaction(ctx, func(tx Builder) error {
+			results, err := tx.Batch(tx.Update().Table("users").Set("name", "a")).ExecContext(ctx)
+			assert.Equal(t, []BatchResult{{RowsAffected: 5}}, results)
+			return err
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("multi statement", func(t *testing.T) {
+		b, mock := newMockBuilder(t)
+		mock.ExpectExec("INSERT INTO users (name) VALUES ($1);\nDELETE FROM users WHERE id = $2").
+			WithArgs("a", 2).
+			WillReturnResult(sqlmock.NewResult(1, 2))
+
+		results, err := b.Batch(
+			b.Insert().Into("users").Columns("name").Values("a"),
+			NewEmptyBuilder().Delete().From("users").Where("id = ?", 2),
+		).MultiStatement().ExecContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []BatchResult{{RowsAffected: -1}, {RowsAffected: -1}}, results)
+	})
+
+	t.Run("executor", func(t *testing.T) {
+		b, _ := newMockBuilder(t)
+		executor := &fakeBatchExecutor{}
+
+		results, err := b.Batch(
+			b.Insert().Into("users").Columns("name").Values("a"),
+			b.Update().Table("users").Set("name", "b"),
+		).Executor(executor).ExecContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []BatchResult{{RowsAffected: 1}, {RowsAffected: 2}}, results)
+		assert.Equal(t, []BatchQuery{
+			{SQL: "INSERT INTO users (name) VALUES ($1)", Args: []any{"a"}},
+			{SQL: "UPDATE users SET name = $1", Args: []any{"b"}},
+		}, executor.queries)
+	})
+}
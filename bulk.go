@@ -0,0 +1,251 @@
+package ondatra
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const updateManyAlias = "v"
+
+// UpdateMany returns update statements for rows, a slice of structs with db tags, by the rules of StructColumns.
+// Rows are matched by keyColumns or by columns tagged as pk, Columns limits updated columns.
+// Postgres statements are UPDATE ... FROM (VALUES ...), other dialects use CASE for every column.
+// Rows are split into several statements by the parameters limit of the dialect.
+func (b Builder) UpdateMany(table string, keyColumns []string, rows any) ([]Builder, error) {
+	v := reflect.Indirect(reflect.ValueOf(rows))
+	if v.Kind() != reflect.Slice {
+		return nil, ErrRowsNotSlice
+	}
+	if v.Len() == 0 {
+		return nil, nil
+	}
+
+	elemType := v.Type().Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, ErrRowsNotSlice
+	}
+
+	fields := b.updateManyFields(elemType, keyColumns)
+	if len(fields.keys) == 0 {
+		return nil, ErrKeyColumnsNotSet
+	}
+
+	paramsPerRow := len(fields.keys) + len(fields.columns)
+	if b.dialect != DialectPostgres {
+		paramsPerRow = len(fields.columns)*(len(fields.keys)+1) + len(fields.keys)
+	}
+	batchSize := max(1, b.dialect.MaxParameters()/max(1, paramsPerRow))
+
+	var statements []Builder
+	for start := 0; start < v.Len(); start += batchSize {
+		end := min(start+batchSize, v.Len())
+
+		batch := make([]reflect.Value, 0, end-start)
+		for i := start; i < end; i++ {
+			row := v.Index(i)
+			if row.Kind() == reflect.Pointer && row.IsNil() {
+				return nil, fmt.Errorf("%w: row %d is nil", ErrRowsNotSlice, i)
+			}
+			batch = append(batch, reflect.Indirect(row))
+		}
+
+		statement := b.Update().Table(table)
+		statement.columns = nil
+		statement.updateValues = slices.Clone(b.updateValues)
+		statement.whereExpr = slices.Clone(b.whereExpr)
+		statement.from = slices.Clone(b.from)
+		if b.dialect == DialectPostgres {
+			statement = statement.updateManyFrom(table, fields, batch)
+		} else {
+			statement = statement.updateManyCase(fields, batch)
+		}
+		statements = append(statements, statement)
+	}
+
+	return statements, nil
+}
+
+// ExecUpdateMany executes statements of UpdateMany, several statements are executed in one transaction
+func (b Builder) ExecUpdateMany(ctx context.Context, table string, keyColumns []string, rows any) (int64, error) {
+	statements, err := b.UpdateMany(table, keyColumns, rows)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(statements) <= 1 {
+		return execStatements(ctx, statements)
+	}
+
+	var affected int64
+	err = b.transaction(ctx, func(tx Builder) error {
+		for i := range statements {
+			statements[i].writerConn = tx.writerConn
+			statements[i].readerConn = nil
+		}
+		affected, err = execStatements(ctx, statements)
+		return err
+	})
+	return affected, err
+}
+
+func execStatements(ctx context.Context, statements []Builder) (int64, error) {
+	var affected int64
+	for i := range statements {
+		result, err := statements[i].ExecContext(ctx)
+		if err != nil {
+			return affected, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return affected, err
+		}
+		affected += rowsAffected
+	}
+	return affected, nil
+}
+
+type updateManyFields struct {
+	keys           []string
+	keyIndexes     []int
+	columns        []string
+	columnIndexes  []int
+	defaultColumns []string
+}
+
+func (b Builder) updateManyFields(t reflect.Type, keyColumns []string) updateManyFields {
+	var fields updateManyFields
+	var primaryKeys []string
+	var primaryKeyIndexes []int
+	keyIndexes := make(map[string]int)
+
+	for i := 0; i < t.NumField(); i++ {
+		dbTags := strings.Split(t.Field(i).Tag.Get("db"), ",")
+		if len(dbTags) == 0 || !slices.Contains(dbTags, modelTagColumn) {
+			continue
+		}
+		columnName := dbTags[0]
+
+		if slices.Contains(keyColumns, columnName) {
+			keyIndexes[columnName] = i
+			continue
+		}
+
+		if slices.Contains(dbTags, modelTagPrimaryKey) {
+			primaryKeys = append(primaryKeys, columnName)
+			primaryKeyIndexes = append(primaryKeyIndexes, i)
+			continue
+		}
+
+		if len(b.columns) > 0 && !slices.Contains(b.columns, columnName) {
+			continue
+		}
+
+		if columnName == ColumnCreatedAt {
+			continue
+		}
+		if columnName == ColumnUpdatedAt {
+			fields.defaultColumns = append(fields.defaultColumns, columnName)
+			continue
+		}
+
+		fields.columns = append(fields.columns, columnName)
+		fields.columnIndexes = append(fields.columnIndexes, i)
+	}
+
+	if len(keyColumns) == 0 {
+		fields.keys = primaryKeys
+		fields.keyIndexes = primaryKeyIndexes
+		return fields
+	}
+
+	for _, key := range keyColumns {
+		i, ok := keyIndexes[key]
+		if !ok {
+			return updateManyFields{}
+		}
+		fields.keys = append(fields.keys, key)
+		fields.keyIndexes = append(fields.keyIndexes, i)
+	}
+	return fields
+}
+
+// updateManyFrom renders UPDATE t SET a = v.a FROM (VALUES ...) AS v(...) WHERE t.id = v.id,
+// values are preceded by empty select from the table so postgres resolves parameter types by the table columns
+func (b Builder) updateManyFrom(table string, fields updateManyFields, rows []reflect.Value) Builder {
+	columns := append(slices.Clone(fields.keys), fields.columns...)
+	indexes := append(slices.Clone(fields.keyIndexes), fields.columnIndexes...)
+
+	placeholders := "(" + strings.TrimRight(strings.Repeat("?,", len(columns)), ",") + ")"
+	values := make([]string, len(rows))
+	args := make([]any, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		values[i] = placeholders
+		for _, index := range indexes {
+			args = append(args, row.Field(index).Interface())
+		}
+	}
+
+	b.from = append(b.from, NewExpr(fmt.Sprintf(
+		"(SELECT %s FROM %s WHERE false UNION ALL VALUES %s) AS %s(%s)",
+		strings.Join(columns, ", "), table, strings.Join(values, ","), updateManyAlias, strings.Join(columns, ", "),
+	), args...))
+
+	for _, column := range fields.columns {
+		b.updateValues = append(b.updateValues, NewExpr(fmt.Sprintf("%s = %s.%s", column, updateManyAlias, column)))
+	}
+	for _, column := range fields.defaultColumns {
+		b.updateValues = append(b.updateValues, NewExpr(fmt.Sprintf("%s = DEFAULT", column)))
+	}
+	qualifier := tableAlias(table)
+	for _, key := range fields.keys {
+		b.whereExpr = append(b.whereExpr, NewExpr(fmt.Sprintf("%s.%s = %s.%s", qualifier, key, updateManyAlias, key)))
+	}
+
+	return b
+}
+
+// updateManyCase renders UPDATE t SET a = CASE WHEN id = ? THEN ? ... END WHERE id IN (...)
+func (b Builder) updateManyCase(fields updateManyFields, rows []reflect.Value) Builder {
+	conditions := make([]Expr, len(rows))
+	keyValues := make([]any, 0, len(rows))
+	for i, row := range rows {
+		keyConditions := make([]Expr, len(fields.keys))
+		for j, key := range fields.keys {
+			value := row.Field(fields.keyIndexes[j]).Interface()
+			keyConditions[j] = NewExpr(fmt.Sprintf("%s = ?", key), value)
+			keyValues = append(keyValues, value)
+		}
+		conditions[i] = keyConditions[0]
+		if len(keyConditions) > 1 {
+			conditions[i] = AND(keyConditions...)
+		}
+	}
+
+	for j, column := range fields.columns {
+		caseExpr := Case()
+		for i, row := range rows {
+			caseExpr = caseExpr.When(conditions[i], row.Field(fields.columnIndexes[j]).Interface())
+		}
+		b.updateValues = append(b.updateValues, NewExpr(fmt.Sprintf("%s = ?", column), caseExpr))
+	}
+	for _, column := range fields.defaultColumns {
+		b.updateValues = append(b.updateValues, NewExpr(fmt.Sprintf("%s = DEFAULT", column)))
+	}
+
+	if len(fields.keys) == 1 {
+		b.whereExpr = append(b.whereExpr, NewExpr(
+			fmt.Sprintf("%s IN (%s)", fields.keys[0], strings.TrimRight(strings.Repeat("?,", len(keyValues)), ",")),
+			keyValues...,
+		))
+	} else {
+		b.whereExpr = append(b.whereExpr, OR(conditions...))
+	}
+
+	return b
+}
@@ -0,0 +1,126 @@
+package ondatra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type updateManyUser struct {
+	ID        int64     `db:"id,column,pk"`
+	Name      string    `db:"name,column"`
+	Status    string    `db:"status,column"`
+	Internal  string    `db:"internal"`
+	CreatedAt time.Time `db:"created_at,column,default"`
+	UpdatedAt time.Time `db:"updated_at,column,default"`
+}
+
+func TestBuilder_UpdateMany(t *testing.T) {
+	users := []updateManyUser{
+		{ID: 1, Name: "a", Status: "active"},
+		{ID: 2, Name: "b", Status: "blocked"},
+	}
+
+	var tests = []struct {
+		name        string
+		builder     Builder
+		table       string
+		keyColumns  []string
+		expectQuery string
+		expectArgs  []any
+	}{
+		{
+			name:    "postgres",
+			builder: NewEmptyBuilder().Dialect(DialectPostgres).PlaceholderFormat(Dollar),
+			expectQuery: "UPDATE users SET name = v.name, status = v.status, updated_at = DEFAULT " +
+				"FROM (SELECT id, name, status FROM users WHERE false UNION ALL VALUES ($1,$2,$3),($4,$5,$6)) " +
+				"AS v(id, name, status) WHERE users.id = v.id",
+			expectArgs: []any{int64(1), "a", "active", int64(2), "b", "blocked"},
+		}, {
+			name:       "postgres key columns",
+			builder:    NewEmptyBuilder().Dialect(DialectPostgres).Columns("status"),
+			keyColumns: []string{"name"},
+			expectQuery: "UPDATE users SET status = v.status " +
+				"FROM (SELECT name, status FROM users WHERE false UNION ALL VALUES (?,?),(?,?)) " +
+				"AS v(name, status) WHERE users.name = v.name",
+			expectArgs: []any{"a", "active", "b", "blocked"},
+		}, {
+			name:    "postgres schema",
+			builder: NewEmptyBuilder().Dialect(DialectPostgres).Columns("status"),
+			table:   "public.users",
+			expectQuery: "UPDATE public.users SET status = v.status " +
+				"FROM (SELECT id, status FROM public.users WHERE false UNION ALL VALUES (?,?),(?,?)) " +
+				"AS v(id, status) WHERE public.users.id = v.id",
+			expectArgs: []any{int64(1), "active", int64(2), "blocked"},
+		}, {
+			name:    "postgres alias",
+			builder: NewEmptyBuilder().Dialect(DialectPostgres).Columns("status"),
+			table:   "users u",
+			expectQuery: "UPDATE users u SET status = v.status " +
+				"FROM (SELECT id, status FROM users u WHERE false UNION ALL VALUES (?,?),(?,?)) " +
+				"AS v(id, status) WHERE u.id = v.id",
+			expectArgs: []any{int64(1), "active", int64(2), "blocked"},
+		}, {
+			name:    "case",
+			builder: NewEmptyBuilder().Dialect(DialectMySQL),
+			expectQuery: "UPDATE users SET name = CASE WHEN id = ? THEN ? WHEN id = ? THEN ? END, " +
+				"status = CASE WHEN id = ? THEN ? WHEN id = ? THEN ? END, updated_at = DEFAULT " +
+				"WHERE id IN (?,?)",
+			expectArgs: []any{int64(1), "a", int64(2), "b", int64(1), "active", int64(2), "blocked", int64(1), int64(2)},
+		}, {
+			name:       "case composite key",
+			builder:    NewEmptyBuilder().Dialect(DialectSQLite).Columns("status"),
+			keyColumns: []string{"id", "name"},
+			expectQuery: "UPDATE users SET status = CASE WHEN (id = ? AND name = ?) THEN ? " +
+				"WHEN (id = ? AND name = ?) THEN ? END " +
+				"WHERE ((id = ? AND name = ?) OR (id = ? AND name = ?))",
+			expectArgs: []any{int64(1), "a", "active", int64(2), "b", "blocked", int64(1), "a", int64(2), "b"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := test.table
+			if table == "" {
+				table = "users"
+			}
+			statements, err := test.builder.UpdateMany(table, test.keyColumns, users)
+			assert.NoError(t, err)
+			assert.Len(t, statements, 1)
+
+			query, args, err := statements[0].ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
+
+func TestBuilder_UpdateManyBatches(t *testing.T) {
+	users := make([]*updateManyUser, 1000)
+	for i := range users {
+		users[i] = &updateManyUser{ID: int64(i), Name: "name", Status: "active"}
+	}
+
+	// 2 columns with 1 key take 5 parameters per row, 2100 / 5 = 420 rows per statement
+	statements, err := NewEmptyBuilder().Dialect(DialectSQLServer).UpdateMany("users", nil, users)
+	assert.NoError(t, err)
+	assert.Len(t, statements, 3)
+
+	for i, rows := range []int{420, 420, 160} {
+		_, args, err := statements[i].ToSQL()
+		assert.NoError(t, err)
+		assert.Len(t, args, rows*5)
+	}
+}
+
+func TestBuilder_UpdateManyErrors(t *testing.T) {
+	_, err := NewEmptyBuilder().UpdateMany("users", nil, updateManyUser{})
+	assert.ErrorIs(t, err, ErrRowsNotSlice)
+
+	_, err = NewEmptyBuilder().UpdateMany("users", []string{"unknown"}, []updateManyUser{{}})
+	assert.ErrorIs(t, err, ErrKeyColumnsNotSet)
+
+	_, err = NewEmptyBuilder().UpdateMany("users", nil, []*updateManyUser{{ID: 1}, nil})
+	assert.ErrorIs(t, err, ErrRowsNotSlice)
+}
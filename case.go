@@ -0,0 +1,78 @@
+package ondatra
+
+import "strings"
+
+// CaseExpr is a CASE expression builder. Conditions, operand and results can be values,
+// Expr or Builder, values are passed as arguments and Builder is rendered as scalar subquery.
+type CaseExpr struct {
+	operand   any
+	whens     [][2]any
+	elseSet   bool
+	elseValue any
+}
+
+// Case starts searched form, e.g. CASE WHEN a = ? THEN ? END
+func Case() CaseExpr {
+	return CaseExpr{}
+}
+
+// CaseOf starts simple form, e.g. CASE status WHEN ? THEN ? END
+func CaseOf(operand any) CaseExpr {
+	return CaseExpr{operand: operand}
+}
+
+func (c CaseExpr) When(condition, value any) CaseExpr {
+	c.whens = append(c.whens, [2]any{condition, value})
+	return c
+}
+
+func (c CaseExpr) Else(value any) CaseExpr {
+	c.elseSet = true
+	c.elseValue = value
+	return c
+}
+
+func (c CaseExpr) ToSQL() (string, []any, error) {
+	if len(c.whens) == 0 {
+		return "", nil, ErrCaseWithoutWhen
+	}
+
+	var buffer strings.Builder
+	var args []any
+
+	buffer.WriteString("CASE")
+	if c.operand != nil {
+		buffer.WriteString(" ?")
+		args = append(args, caseArgument(c.operand))
+	}
+	for _, when := range c.whens {
+		buffer.WriteString(" WHEN ? THEN ?")
+		args = append(args, caseArgument(when[0]), caseArgument(when[1]))
+	}
+	if c.elseSet {
+		buffer.WriteString(" ELSE ?")
+		args = append(args, caseArgument(c.elseValue))
+	}
+	buffer.WriteString(" END")
+
+	return NewExpr(buffer.String(), args...).ToSQL()
+}
+
+func (c CaseExpr) bindSubqueries(bind func(Builder) Builder) Expr {
+	whens := make([][2]any, len(c.whens))
+	for i, when := range c.whens {
+		bound := bindSubqueriesArgs(when[:], bind)
+		whens[i] = [2]any{bound[0], bound[1]}
+	}
+	c.whens = whens
+	bound := bindSubqueriesArgs([]any{c.operand, c.elseValue}, bind)
+	c.operand, c.elseValue = bound[0], bound[1]
+	return c
+}
+
+func caseArgument(value any) any {
+	if b, ok := value.(Builder); ok {
+		return Subquery(b)
+	}
+	return value
+}
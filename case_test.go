@@ -0,0 +1,62 @@
+package ondatra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCase(t *testing.T) {
+	status := NewColumn[int]("orders", "status")
+
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+	}{
+		{
+			name: "update",
+			builder: NewEmptyBuilder().
+				Update().
+				Table("a").
+				Set("c1", CaseOf(NewExpr("status")).When(1, 2).When(2, 1)).
+				Set("c2", Case().When(NewExpr("a = 2"), "foo").When(NewExpr("a = ?", 3), "bar").Else(nil)).
+				SetExpr(status.Set.Value(0)).
+				Where("d = ?", 4),
+			expectQuery: "UPDATE a SET c1 = CASE status WHEN ? THEN ? WHEN ? THEN ? END, " +
+				"c2 = CASE WHEN a = 2 THEN ? WHEN a = ? THEN ? ELSE ? END, status = ? WHERE d = ?",
+			expectArgs: []any{1, 2, 2, 1, "foo", 3, "bar", nil, 0, 4},
+		}, {
+			name: "select nested",
+			builder: NewEmptyBuilder().
+				Select("id").
+				SelectColumn("? AS label", CaseOf(status).
+					When(1, Case().When(NewExpr("total > ?", 100), "big").Else("small")).
+					When(2, NewEmptyBuilder().Select("name").From("statuses").Where("id = ?", 2)).
+					Else(NewExpr("'other'")),
+				).
+				From("orders").
+				WhereExpr(NewExpr("? = ?", Case().When(status.EQ.Value(3), true).Else(false), true)).
+				OrderByArgs("? ASC", CaseOf(status).When(1, 0).Else(1)),
+			expectQuery: "SELECT id, CASE \"orders\".status WHEN ? THEN CASE WHEN total > ? THEN ? ELSE ? END " +
+				"WHEN ? THEN (SELECT name FROM statuses WHERE id = ?) ELSE 'other' END AS label " +
+				"FROM orders WHERE CASE WHEN \"orders\".status = ? THEN ? ELSE ? END = ? " +
+				"ORDER BY CASE \"orders\".status WHEN ? THEN ? ELSE ? END ASC",
+			expectArgs: []any{1, 100, "big", "small", 2, 2, 3, true, false, true, 1, 0, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
+
+func TestCaseWithoutWhen(t *testing.T) {
+	_, _, err := Case().Else(1).ToSQL()
+	assert.ErrorIs(t, err, ErrCaseWithoutWhen)
+}
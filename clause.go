@@ -4,6 +4,33 @@ type Clause interface {
 	Apply(b Builder) Builder
 }
 
+// ClauseFunc is an adapter to use a function as Clause
+type ClauseFunc func(b Builder) Builder
+
+func (f ClauseFunc) Apply(b Builder) Builder {
+	return f(b)
+}
+
+// Clauses is a list of clauses applied in order, nil clauses are skipped
+type Clauses []Clause
+
+func (c Clauses) Apply(b Builder) Builder {
+	for i := range c {
+		if c[i] != nil {
+			b = c[i].Apply(b)
+		}
+	}
+	return b
+}
+
+// If applies clauses only when condition is true
+func If(condition bool, clauses ...Clause) Clause {
+	if !condition {
+		return Clauses(nil)
+	}
+	return Clauses(clauses)
+}
+
 type prefixClause struct {
 	rawSQL string
 	args   []any
@@ -94,6 +121,20 @@ func SelectColumn(rawSQL string, args ...any) Clause {
 	}
 }
 
+type selectExprClause struct {
+	expr []Expr
+}
+
+func (c selectExprClause) Apply(b Builder) Builder {
+	return b.SelectExpr(c.expr...)
+}
+
+func SelectExpr(expr ...Expr) Clause {
+	return selectExprClause{
+		expr: expr,
+	}
+}
+
 type optionsClause struct {
 	options []string
 }
@@ -148,6 +189,34 @@ func Set(column string, value any) Clause {
 	}
 }
 
+type setMapClause struct {
+	clauses map[string]any
+}
+
+func (c setMapClause) Apply(b Builder) Builder {
+	return b.SetMap(c.clauses)
+}
+
+func SetMap(clauses map[string]any) Clause {
+	return setMapClause{
+		clauses: clauses,
+	}
+}
+
+type valuesMapsClause struct {
+	rows []map[string]any
+}
+
+func (c valuesMapsClause) Apply(b Builder) Builder {
+	return b.ValuesMaps(c.rows)
+}
+
+func ValuesMaps(rows ...map[string]any) Clause {
+	return valuesMapsClause{
+		rows: rows,
+	}
+}
+
 type setExprClause struct {
 	expr []Expr
 }
@@ -192,6 +261,49 @@ func FromSelect(from Expr, alias string) Clause {
 	}
 }
 
+type updateFromClause struct {
+	tables []string
+}
+
+func (c updateFromClause) Apply(b Builder) Builder {
+	return b.UpdateFrom(c.tables...)
+}
+
+func UpdateFrom(tables ...string) Clause {
+	return updateFromClause{
+		tables: tables,
+	}
+}
+
+type updateFromSelectClause struct {
+	from  Expr
+	alias string
+}
+
+func (c updateFromSelectClause) Apply(b Builder) Builder {
+	return b.UpdateFromSelect(c.from, c.alias)
+}
+
+func UpdateFromSelect(from Expr, alias string) Clause {
+	return updateFromSelectClause{
+		from:  from,
+		alias: alias,
+	}
+}
+
+func DeleteUsing(tables ...string) Clause {
+	return updateFromClause{
+		tables: tables,
+	}
+}
+
+func DeleteUsingSelect(from Expr, alias string) Clause {
+	return updateFromSelectClause{
+		from:  from,
+		alias: alias,
+	}
+}
+
 type joinRawClause struct {
 	rawSQL string
 	args   []any
@@ -215,7 +327,7 @@ type joinClause struct {
 }
 
 func (c joinClause) Apply(b Builder) Builder {
-	return b.Join(c.joinType, c.join)
+	return b.Join(c.joinType, c.join, c.args...)
 }
 
 func Join(joinType, join string, args ...any) Clause {
@@ -226,6 +338,43 @@ func Join(joinType, join string, args ...any) Clause {
 	}
 }
 
+type joinSelectClause struct {
+	joinType string
+	query    Builder
+	alias    string
+	on       string
+	args     []any
+	lateral  bool
+}
+
+func (c joinSelectClause) Apply(b Builder) Builder {
+	if c.lateral {
+		return b.JoinLateral(c.joinType, c.query, c.alias, c.on, c.args...)
+	}
+	return b.JoinSelect(c.joinType, c.query, c.alias, c.on, c.args...)
+}
+
+func JoinSelect(joinType string, query Builder, alias, on string, args ...any) Clause {
+	return joinSelectClause{
+		joinType: joinType,
+		query:    query,
+		alias:    alias,
+		on:       on,
+		args:     args,
+	}
+}
+
+func JoinLateral(joinType string, query Builder, alias, on string, args ...any) Clause {
+	return joinSelectClause{
+		joinType: joinType,
+		query:    query,
+		alias:    alias,
+		on:       on,
+		args:     args,
+		lateral:  true,
+	}
+}
+
 type joinBuilderClause struct {
 	joinExpr []JoinExpr
 }
@@ -284,6 +433,20 @@ func GroupBy(groupBys ...string) Clause {
 	}
 }
 
+type groupByExprClause struct {
+	expr []Expr
+}
+
+func (c groupByExprClause) Apply(b Builder) Builder {
+	return b.GroupByExpr(c.expr...)
+}
+
+func GroupByExpr(expr ...Expr) Clause {
+	return groupByExprClause{
+		expr: expr,
+	}
+}
+
 type havingClause struct {
 	rawSQL string
 	args   []any
@@ -300,6 +463,36 @@ func Having(rawSQL string, args ...any) Clause {
 	}
 }
 
+type havingExprClause struct {
+	expr []Expr
+}
+
+func (c havingExprClause) Apply(b Builder) Builder {
+	return b.HavingExpr(c.expr...)
+}
+
+func HavingExpr(expr ...Expr) Clause {
+	return havingExprClause{
+		expr: expr,
+	}
+}
+
+type windowClause struct {
+	name   string
+	window WindowSpec
+}
+
+func (c windowClause) Apply(b Builder) Builder {
+	return b.Window(c.name, c.window)
+}
+
+func Window(name string, window WindowSpec) Clause {
+	return windowClause{
+		name:   name,
+		window: window,
+	}
+}
+
 type orderByClause struct {
 	orderBy []string
 }
@@ -374,6 +567,71 @@ func LimitOffset(limit, offset int64) Clause {
 	}
 }
 
+type returningClause struct {
+	columns []string
+}
+
+func (c returningClause) Apply(b Builder) Builder {
+	return b.Returning(c.columns...)
+}
+
+func Returning(columns ...string) Clause {
+	return returningClause{
+		columns: columns,
+	}
+}
+
+type lockClause struct {
+	strength string
+	wait     string
+}
+
+func (c lockClause) Apply(b Builder) Builder {
+	if c.strength != "" {
+		b = b.lockRows(c.strength)
+	}
+	if c.wait != "" {
+		b.lock.wait = c.wait
+	}
+	return b
+}
+
+func ForUpdate() Clause {
+	return lockClause{
+		strength: LockUpdate,
+	}
+}
+
+func ForNoKeyUpdate() Clause {
+	return lockClause{
+		strength: LockNoKeyUpdate,
+	}
+}
+
+func ForShare() Clause {
+	return lockClause{
+		strength: LockShare,
+	}
+}
+
+func ForKeyShare() Clause {
+	return lockClause{
+		strength: LockKeyShare,
+	}
+}
+
+func SkipLocked() Clause {
+	return lockClause{
+		wait: lockSkipLocked,
+	}
+}
+
+func NoWait() Clause {
+	return lockClause{
+		wait: lockNoWait,
+	}
+}
+
 type suffixClause struct {
 	rawSQL string
 	args   []any
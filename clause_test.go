@@ -55,6 +55,29 @@ func TestClause(t *testing.T) {
 				"GROUP BY l HAVING m = n ORDER BY ? DESC, o ASC, p DESC LIMIT 12 OFFSET 13 " +
 				"FETCH FIRST ? ROWS ONLY",
 			expectArgs: []any{0, 1, 2, 3, 100, 101, 102, 103, 4, 5, 6, 7, 8, 9, 10, 11, 1, 14},
+		}, {
+			name: "join with args",
+			clauses: []Clause{
+				SelectColumns("id"),
+				Join(JoinLeft, "j1 ON j1.id = test.id AND j1.status = ?", "active"),
+				Where("test.x = ?", 1),
+			},
+			expectQuery: "SELECT id FROM test LEFT JOIN j1 ON j1.id = test.id AND j1.status = ? WHERE test.x = ?",
+			expectArgs:  []any{"active", 1},
+		}, {
+			name: "composition",
+			clauses: []Clause{
+				SelectColumns("id"),
+				If(true, Where("a = ?", 1), OrderBy("b")),
+				If(false, Where("c = ?", 2)),
+				Scope("active", Where("deleted_at IS NULL"), If(true, Limit(10))),
+				Clauses{Where("d = ?", 3), nil},
+				ClauseFunc(func(b Builder) Builder {
+					return b.Where("e = ?", 4)
+				}),
+			},
+			expectQuery: "SELECT id FROM test WHERE a = ? AND deleted_at IS NULL AND d = ? AND e = ? ORDER BY b LIMIT 10",
+			expectArgs:  []any{1, 3, 4},
 		},
 	}
 
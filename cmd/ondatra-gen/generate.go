@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/stepanbukhtii/ondatra"
+)
+
+const (
+	generatedHeader = "// Code generated by ondatra-gen. DO NOT EDIT."
+	generatedSuffix = ".gen.go"
+	ondatraImport   = "github.com/stepanbukhtii/ondatra"
+)
+
+// tableFile is a generated file of one table, imports are import specs, e.g. "time"
+type tableFile struct {
+	Package string
+	Imports []string
+	Table   string
+	GoName  string
+	Model   string
+	Columns []columnField
+	Joins   []joinFunc
+}
+
+// columnField is a column of Table, Column[T] struct and model
+type columnField struct {
+	Name       string
+	GoName     string
+	GoType     string
+	ColumnType string
+	Tag        string
+}
+
+// joinFunc is a JoinExpr constructor of foreign key
+type joinFunc struct {
+	Name         string
+	ForeignKey   string
+	RefTable     string
+	TableExpr    string
+	Field        string
+	RelatedField string
+}
+
+var fileTemplate = template.Must(template.New("file").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+}).Parse(generatedHeader + `
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+
+// {{.GoName}}Table is the {{.Table}} table
+var {{.GoName}}Table = ondatra.NewTable({{quote .Table}}, []string{
+{{- range $i, $c := .Columns}}{{if $i}}, {{end}}{{quote $c.Name}}{{end -}}
+})
+
+// {{.GoName}}Columns are typed columns of the {{.Table}} table
+var {{.GoName}}Columns = struct {
+{{- range .Columns}}
+	{{.GoName}} ondatra.Column[{{.ColumnType}}]
+{{- end}}
+}{
+{{- range .Columns}}
+	{{.GoName}}: ondatra.NewColumn[{{.ColumnType}}]({{quote $.Table}}, {{quote .Name}}),
+{{- end}}
+}
+{{- if .Model}}
+
+// {{.Model}} is a row of the {{.Table}} table
+type {{.Model}} struct {
+{{- range .Columns}}
+	{{.GoName}} {{.GoType}} ` + "`" + `db:{{quote .Tag}}` + "`" + `
+{{- end}}
+}
+{{- end}}
+{{- range .Joins}}
+
+// {{.Name}} joins {{.RefTable}} to {{$.Table}} by {{.ForeignKey}}
+func {{.Name}}(joinType, alias string) ondatra.JoinExpr {
+	return ondatra.NewJoinBuilder({{quote $.Table}}).NewJoin(joinType, {{.TableExpr}}, alias, {{quote .Field}}, {{quote .RelatedField}})
+}
+{{- end}}
+`))
+
+// tableFilesFromSchema returns files with Table, columns, model and joins of tables of the schema,
+// joins of foreign keys with several columns are not generated
+func tableFilesFromSchema(packageName string, dialect ondatra.Dialect, schema ondatra.Schema, tables []string) []tableFile {
+	generated := make(map[string]bool)
+	for _, table := range schema.Tables {
+		generated[table.Name] = len(tables) == 0 || slices.Contains(tables, table.Name)
+	}
+
+	var files []tableFile
+	for _, table := range schema.Tables {
+		if !generated[table.Name] {
+			continue
+		}
+
+		file := tableFile{
+			Package: packageName,
+			Table:   table.Name,
+			GoName:  pascalCase(table.Name),
+		}
+		file.Model = singular(file.GoName)
+		if file.Model == file.GoName {
+			file.Model += "Row"
+		}
+
+		imports := []string{strconv.Quote(ondatraImport)}
+		goNames := make(map[string]bool)
+		for _, column := range table.Columns {
+			// sqlite reports columns of primary key as nullable unless they are declared NOT NULL
+			t := goTypeOf(dialect, column.Type, column.Nullable && !column.PrimaryKey)
+			if t.importPath != "" && !slices.Contains(imports, strconv.Quote(t.importPath)) {
+				imports = append(imports, strconv.Quote(t.importPath))
+			}
+			file.Columns = append(file.Columns, columnField{
+				Name:       column.Name,
+				GoName:     uniqueName(goNames, pascalCase(column.Name)),
+				GoType:     t.name,
+				ColumnType: t.columnType(),
+				Tag:        columnTag(dialect, table, column),
+			})
+		}
+		file.Imports = groupImports(imports)
+
+		joinNames := make(map[string]bool)
+		for _, foreignKey := range table.ForeignKeys {
+			if len(foreignKey.Columns) != 1 || len(foreignKey.RefColumns) != 1 {
+				continue
+			}
+			refTable, ok := schema.Table(foreignKey.RefTable)
+			if !ok {
+				continue
+			}
+
+			tableExpr := pascalCase(refTable.Name) + "Table"
+			if !generated[refTable.Name] {
+				tableExpr = newTableExpr(refTable)
+			}
+
+			name := strings.TrimSuffix(foreignKey.Columns[0], "_id")
+			if name == foreignKey.Columns[0] || name == "" {
+				name = refTable.Name
+			}
+			file.Joins = append(file.Joins, joinFunc{
+				Name:         uniqueName(joinNames, "Join"+file.GoName+pascalCase(name)),
+				ForeignKey:   foreignKey.Name,
+				RefTable:     refTable.Name,
+				TableExpr:    tableExpr,
+				Field:        foreignKey.RefColumns[0],
+				RelatedField: foreignKey.Columns[0],
+			})
+		}
+
+		files = append(files, file)
+	}
+	return files
+}
+
+// columnTag returns db tag of model field, integer primary key of one column is tagged as default
+// like a column with default value, so zero value is generated by database
+func columnTag(dialect ondatra.Dialect, table ondatra.TableSchema, column ondatra.ColumnSchema) string {
+	tags := []string{column.Name, "column"}
+	if column.PrimaryKey {
+		tags = append(tags, "pk")
+	}
+
+	base := baseGoType(dialect, column.Type)
+	integerKey := len(table.PrimaryKey) == 1 && column.PrimaryKey &&
+		(base == typeInt16 || base == typeInt32 || base == typeInt64)
+	if column.Default.Valid || integerKey {
+		tags = append(tags, "default")
+	}
+
+	if column.Name == ondatra.TenantColumn {
+		tags = append(tags, "tenant")
+	}
+	return strings.Join(tags, ",")
+}
+
+func newTableExpr(table ondatra.TableSchema) string {
+	columns := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		columns[i] = strconv.Quote(column.Name)
+	}
+	return fmt.Sprintf("ondatra.NewTable(%s, []string{%s})", strconv.Quote(table.Name), strings.Join(columns, ", "))
+}
+
+// groupImports returns import specs sorted by path, standard packages are separated from other packages by empty line
+func groupImports(specs []string) []string {
+	importPath := func(spec string) string {
+		return spec[strings.Index(spec, `"`):]
+	}
+	byPath := func(a, b string) int {
+		return strings.Compare(importPath(a), importPath(b))
+	}
+
+	var std, other []string
+	for _, spec := range specs {
+		path, err := strconv.Unquote(importPath(spec))
+		if err == nil && !strings.Contains(strings.Split(path, "/")[0], ".") {
+			std = append(std, spec)
+		} else {
+			other = append(other, spec)
+		}
+	}
+	slices.SortFunc(std, byPath)
+	slices.SortFunc(other, byPath)
+	if len(std) > 0 && len(other) > 0 {
+		std = append(std, "")
+	}
+	return append(std, other...)
+}
+
+// uniqueName returns name with number suffix when the name is already used
+func uniqueName(used map[string]bool, name string) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// render returns formatted source of the file
+func (f tableFile) render() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, f); err != nil {
+		return nil, err
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", f.Table, err)
+	}
+	return source, nil
+}
+
+// fileName returns name of generated file of the table
+func (f tableFile) fileName() string {
+	return snakeCase(f.GoName) + generatedSuffix
+}
+
+// writeFiles writes files into dir, files with the same content are not rewritten. With prune generated files
+// of dir which are not in files are removed, they are files of dropped tables or renamed structs.
+func writeFiles(dir string, files []tableFile, prune bool) ([]string, error) {
+	var written []string
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.fileName())
+		source, err := file.render()
+		if err != nil {
+			return written, err
+		}
+
+		path := filepath.Join(dir, file.fileName())
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, source) {
+			continue
+		}
+		if err = os.WriteFile(path, source, 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	if !prune {
+		return written, nil
+	}
+	stale, err := filepath.Glob(filepath.Join(dir, "*"+generatedSuffix))
+	if err != nil {
+		return written, err
+	}
+	for _, path := range stale {
+		if slices.Contains(names, filepath.Base(path)) {
+			continue
+		}
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return written, err
+		}
+		if !bytes.HasPrefix(source, []byte(generatedHeader)) {
+			continue
+		}
+		if err = os.Remove(path); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
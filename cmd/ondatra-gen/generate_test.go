@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stepanbukhtii/ondatra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const expectedOrdersFile = `// Code generated by ondatra-gen. DO NOT EDIT.
+
+package models
+
+import (
+	"database/sql"
+
+	"github.com/shopspring/decimal"
+	"github.com/stepanbukhtii/ondatra"
+)
+
+// OrdersTable is the orders table
+var OrdersTable = ondatra.NewTable("orders", []string{"id", "user_id", "amount", "note"})
+
+// OrdersColumns are typed columns of the orders table
+var OrdersColumns = struct {
+	ID     ondatra.Column[int64]
+	UserID ondatra.Column[int64]
+	Amount ondatra.Column[decimal.Decimal]
+	Note   ondatra.Column[sql.NullString]
+}{
+	ID:     ondatra.NewColumn[int64]("orders", "id"),
+	UserID: ondatra.NewColumn[int64]("orders", "user_id"),
+	Amount: ondatra.NewColumn[decimal.Decimal]("orders", "amount"),
+	Note:   ondatra.NewColumn[sql.NullString]("orders", "note"),
+}
+
+// Order is a row of the orders table
+type Order struct {
+	ID     int64           ` + "`" + `db:"id,column,pk,default"` + "`" + `
+	UserID int64           ` + "`" + `db:"user_id,column"` + "`" + `
+	Amount decimal.Decimal ` + "`" + `db:"amount,column,default"` + "`" + `
+	Note   sql.NullString  ` + "`" + `db:"note,column"` + "`" + `
+}
+
+// JoinOrdersUser joins users to orders by orders_user_id_fkey
+func JoinOrdersUser(joinType, alias string) ondatra.JoinExpr {
+	return ondatra.NewJoinBuilder("orders").NewJoin(joinType, UsersTable, alias, "id", "user_id")
+}
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	dsn := filepath.Join(dir, "app.db")
+
+	db, err := sqlx.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	db.MustExec(`CREATE TABLE users (id INTEGER PRIMARY KEY, tenant_id INTEGER NOT NULL, email TEXT NOT NULL)`)
+	db.MustExec(`CREATE TABLE orders (
+		id INTEGER PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users (id),
+		amount DECIMAL(10, 2) NOT NULL DEFAULT 0,
+		note TEXT
+	)`)
+	require.NoError(t, db.Close())
+
+	out := filepath.Join(dir, "models")
+	cfg := config{driver: "sqlite3", dsn: dsn, out: out}
+
+	written, err := run(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(out, "orders.gen.go"), filepath.Join(out, "users.gen.go")}, written)
+
+	orders, err := os.ReadFile(filepath.Join(out, "orders.gen.go"))
+	require.NoError(t, err)
+	assert.Equal(t, expectedOrdersFile, string(orders))
+
+	users, err := os.ReadFile(filepath.Join(out, "users.gen.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(users), "TenantID int64  `db:\"tenant_id,column,tenant\"`")
+
+	written, err = run(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Empty(t, written)
+
+	cfg.tables = []string{"orders"}
+	cfg.out = filepath.Join(dir, "orders")
+	cfg.packageName = "orders"
+	_, err = run(context.Background(), cfg)
+	require.NoError(t, err)
+	orders, err = os.ReadFile(filepath.Join(cfg.out, "orders.gen.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(orders), `NewJoin(joinType, ondatra.NewTable("users", []string{"id", "tenant_id", "email"}), alias, "id", "user_id")`)
+
+	_, err = run(context.Background(), config{})
+	assert.Error(t, err)
+}
+
+func TestTableFilesFromSchema(t *testing.T) {
+	schema := ondatra.Schema{Tables: []ondatra.TableSchema{{
+		Name: "order_lines",
+		Columns: []ondatra.ColumnSchema{
+			{Name: "order_id", Type: "bigint", PrimaryKey: true},
+			{Name: "line", Type: "integer", PrimaryKey: true},
+			{Name: "payload", Type: "jsonb", Nullable: true},
+		},
+		PrimaryKey: []string{"order_id", "line"},
+		ForeignKeys: []ondatra.ForeignKey{
+			{Name: "order_lines_fkey", Columns: []string{"order_id", "line"}, RefTable: "orders", RefColumns: []string{"id", "line"}},
+		},
+	}}}
+
+	files := tableFilesFromSchema("models", ondatra.DialectPostgres, schema, nil)
+	require.Len(t, files, 1)
+	assert.Equal(t, "OrderLine", files[0].Model)
+	assert.Equal(t, []columnField{
+		{Name: "order_id", GoName: "OrderID", GoType: "int64", ColumnType: "int64", Tag: "order_id,column,pk"},
+		{Name: "line", GoName: "Line", GoType: "int32", ColumnType: "int32", Tag: "line,column,pk"},
+		{Name: "payload", GoName: "Payload", GoType: "[]byte", ColumnType: "any", Tag: "payload,column"},
+	}, files[0].Columns)
+	assert.Empty(t, files[0].Joins)
+	assert.Equal(t, "order_lines.gen.go", files[0].fileName())
+
+	_, err := files[0].render()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,19 @@
+module github.com/stepanbukhtii/ondatra/cmd/ondatra-gen
+
+go 1.21
+
+require (
+	github.com/go-sql-driver/mysql v1.7.1
+	github.com/jmoiron/sqlx v1.3.5
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.22
+	github.com/shopspring/decimal v1.3.1
+	github.com/stepanbukhtii/ondatra v0.0.0-20261019102853-80b9b7cece82
+	github.com/stretchr/testify v1.8.4
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
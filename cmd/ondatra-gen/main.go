@@ -0,0 +1,112 @@
+// Command ondatra-gen generates Table, typed columns, models and join constructors of database tables.
+//
+// From live schema of postgres, mysql or sqlite database, a file per table is written into -out directory:
+//
+//	ondatra-gen -driver postgres -dsn "postgres://localhost/app?sslmode=disable" -out ./models
+//
+// From structs of Go package with fields tagged as column, Table and typed columns are written next to the structs,
+// table name is set by //ondatra:table name comment of the struct:
+//
+//	//go:generate go run github.com/stepanbukhtii/ondatra/cmd/ondatra-gen@latest -src .
+//
+// The command is a module of its own, it is installed or run with a version, e.g. @latest:
+//
+//	go install github.com/stepanbukhtii/ondatra/cmd/ondatra-gen@latest
+//
+// Generated files have .gen.go suffix and are rewritten only when their content changes,
+// generated files of dropped tables are removed.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stepanbukhtii/ondatra"
+)
+
+type config struct {
+	driver      string
+	dsn         string
+	schema      string
+	tables      []string
+	src         string
+	out         string
+	packageName string
+}
+
+func main() {
+	var cfg config
+	var tables string
+	flag.StringVar(&cfg.driver, "driver", "", "database/sql driver: postgres, mysql or sqlite3")
+	flag.StringVar(&cfg.dsn, "dsn", "", "data source name of the database")
+	flag.StringVar(&cfg.schema, "schema", "", "postgres schema or mysql database, the current one by default")
+	flag.StringVar(&tables, "tables", "", "comma separated tables, all tables by default")
+	flag.StringVar(&cfg.src, "src", "", "directory of Go package with structs tagged as column, instead of -driver and -dsn")
+	flag.StringVar(&cfg.out, "out", ".", "output directory of schema files")
+	flag.StringVar(&cfg.packageName, "package", os.Getenv("GOPACKAGE"), "package of schema files, name of -out directory by default")
+	flag.Parse()
+
+	if tables != "" {
+		cfg.tables = strings.Split(tables, ",")
+	}
+
+	written, err := run(context.Background(), cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ondatra-gen:", err)
+		os.Exit(1)
+	}
+	for _, path := range written {
+		fmt.Println(path)
+	}
+}
+
+// run generates files and returns paths of changed files
+func run(ctx context.Context, cfg config) ([]string, error) {
+	if cfg.src != "" {
+		files, err := tableFilesFromSource(cfg.src)
+		if err != nil {
+			return nil, err
+		}
+		return writeFiles(cfg.src, files, true)
+	}
+
+	if cfg.driver == "" || cfg.dsn == "" {
+		return nil, errors.New("-driver and -dsn or -src must be set")
+	}
+
+	db, err := sqlx.Open(cfg.driver, cfg.dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	b := ondatra.NewBuilder(db)
+	schema, err := b.IntrospectSchema(ctx, cfg.schema)
+	if err != nil {
+		return nil, err
+	}
+
+	packageName := cfg.packageName
+	if packageName == "" {
+		out, err := filepath.Abs(cfg.out)
+		if err != nil {
+			return nil, err
+		}
+		packageName = strings.ReplaceAll(filepath.Base(out), "-", "")
+	}
+
+	if err = os.MkdirAll(cfg.out, 0o755); err != nil {
+		return nil, err
+	}
+	files := tableFilesFromSchema(packageName, ondatra.DialectByDriver(cfg.driver), schema, cfg.tables)
+	return writeFiles(cfg.out, files, len(cfg.tables) == 0)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const tableDirective = "ondatra:table"
+
+// tableFilesFromSource returns files with Table and columns of structs of the package in dir which have fields
+// tagged as column. Table name is set by //ondatra:table name comment of the struct, by default it is snake case
+// of the struct name. Generated files of the package are skipped.
+func tableFilesFromSource(dir string) ([]tableFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fileSet := token.NewFileSet()
+	var files []tableFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" ||
+			strings.HasSuffix(name, "_test.go") || strings.HasSuffix(name, generatedSuffix) {
+			continue
+		}
+
+		source, err := parser.ParseFile(fileSet, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if ast.IsGenerated(source) {
+			continue
+		}
+
+		for _, decl := range source.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				file, err := structTableFile(source, typeSpec.Name.Name, doc, structType)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", fileSet.Position(typeSpec.Pos()), err)
+				}
+				if len(file.Columns) > 0 {
+					files = append(files, file)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(files, func(a, b tableFile) int {
+		return strings.Compare(a.GoName, b.GoName)
+	})
+	return files, nil
+}
+
+func structTableFile(source *ast.File, structName string, doc *ast.CommentGroup, structType *ast.StructType) (tableFile, error) {
+	file := tableFile{
+		Package: source.Name.Name,
+		Table:   snakeCase(structName),
+		GoName:  structName,
+	}
+	if doc != nil {
+		for _, comment := range doc.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			if table, ok := strings.CutPrefix(text, tableDirective+" "); ok {
+				file.Table = strings.TrimSpace(table)
+			}
+		}
+	}
+
+	imports := []string{strconv.Quote(ondatraImport)}
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return file, err
+		}
+		dbTags := strings.Split(reflect.StructTag(tag).Get("db"), ",")
+		if !slices.Contains(dbTags, "column") {
+			continue
+		}
+
+		columnType := "any"
+		if comparableExpr(source, field.Type) {
+			columnType = types.ExprString(field.Type)
+			for _, spec := range usedImports(source, field.Type) {
+				if !slices.Contains(imports, spec) {
+					imports = append(imports, spec)
+				}
+			}
+		}
+
+		// fields declared together share the tag, so they name one column
+		file.Columns = append(file.Columns, columnField{
+			Name:       dbTags[0],
+			GoName:     field.Names[0].Name,
+			ColumnType: columnType,
+		})
+	}
+
+	file.Imports = groupImports(imports)
+	return file, nil
+}
+
+// comparableExpr reports whether type expression is a comparable type. Named types are comparable only if they are
+// builtin or known column types, e.g. sql.NullString, other named types may be structs with slices.
+func comparableExpr(source *ast.File, expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return comparableBuiltins[t.Name]
+	case *ast.SelectorExpr:
+		ident, ok := t.X.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		for _, spec := range source.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			if name := importName(importPath); spec.Name != nil && spec.Name.Name == ident.Name ||
+				spec.Name == nil && name == ident.Name {
+				return slices.Contains(comparableTypes, goType{name: name + "." + t.Sel.Name, importPath: importPath, comparable: true})
+			}
+		}
+		return false
+	case *ast.ArrayType:
+		return t.Len != nil && comparableExpr(source, t.Elt)
+	case *ast.StructType:
+		for _, field := range t.Fields.List {
+			if !comparableExpr(source, field.Type) {
+				return false
+			}
+		}
+		return true
+	case *ast.StarExpr, *ast.ChanType, *ast.InterfaceType:
+		return true
+	case *ast.ParenExpr:
+		return comparableExpr(source, t.X)
+	default:
+		return false
+	}
+}
+
+// usedImports returns import specs of packages used by type expression
+func usedImports(source *ast.File, expr ast.Expr) []string {
+	var specs []string
+	ast.Inspect(expr, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := selector.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		for _, spec := range source.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			if spec.Name != nil {
+				if spec.Name.Name == ident.Name {
+					specs = append(specs, spec.Name.Name+" "+spec.Path.Value)
+				}
+				continue
+			}
+			if importName(importPath) == ident.Name {
+				specs = append(specs, spec.Path.Value)
+			}
+		}
+		return false
+	})
+	return specs
+}
+
+// importName returns default name of imported package, major version suffix is skipped
+func importName(importPath string) string {
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = path.Base(path.Dir(importPath))
+	}
+	return strings.TrimPrefix(name, "go-")
+}
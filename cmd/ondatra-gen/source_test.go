@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taggedSource = `package models
+
+import (
+	"database/sql"
+	dec "github.com/shopspring/decimal"
+)
+
+// Account is a customer account
+//
+//ondatra:table accounts
+type Account struct {
+	ID      int64          ` + "`" + `db:"id,column,pk,default"` + "`" + `
+	Name    sql.NullString ` + "`" + `db:"name,column"` + "`" + `
+	Balance dec.Decimal    ` + "`" + `db:"balance,column"` + "`" + `
+	Tags    []string       ` + "`" + `db:"tags,column"` + "`" + `
+	Labels  labels         ` + "`" + `db:"labels,column"` + "`" + `
+	Note    string         ` + "`" + `db:"note"` + "`" + `
+}
+
+type (
+	OrderLine struct {
+		OrderID int64 ` + "`" + `db:"order_id,column,pk"` + "`" + `
+	}
+	options struct {
+		Limit int
+	}
+	labels struct {
+		Values []string
+	}
+)
+`
+
+func TestTableFilesFromSource(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models.go"), []byte(taggedSource), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "models_test.go"), []byte("package models\n\nbroken"), 0o644))
+
+	files, err := tableFilesFromSource(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	assert.Equal(t, tableFile{
+		Package: "models",
+		Imports: []string{`"database/sql"`, "", `dec "github.com/shopspring/decimal"`, `"github.com/stepanbukhtii/ondatra"`},
+		Table:   "accounts",
+		GoName:  "Account",
+		Columns: []columnField{
+			{Name: "id", GoName: "ID", ColumnType: "int64"},
+			{Name: "name", GoName: "Name", ColumnType: "sql.NullString"},
+			{Name: "balance", GoName: "Balance", ColumnType: "dec.Decimal"},
+			{Name: "tags", GoName: "Tags", ColumnType: "any"},
+			{Name: "labels", GoName: "Labels", ColumnType: "any"},
+		},
+	}, files[0])
+	assert.Equal(t, "order_line", files[1].Table)
+
+	written, err := writeFiles(dir, files, true)
+	require.NoError(t, err)
+	assert.Len(t, written, 2)
+
+	stale := filepath.Join(dir, "customer.gen.go")
+	manual := filepath.Join(dir, "manual.gen.go")
+	require.NoError(t, os.WriteFile(stale, []byte(generatedHeader+"\n\npackage models\n"), 0o644))
+	require.NoError(t, os.WriteFile(manual, []byte("package models\n"), 0o644))
+
+	files, err = tableFilesFromSource(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	written, err = writeFiles(dir, files, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{stale}, written)
+	assert.NoFileExists(t, stale)
+	assert.FileExists(t, manual)
+}
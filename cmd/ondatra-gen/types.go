@@ -0,0 +1,206 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/stepanbukhtii/ondatra"
+)
+
+// goType is a Go type of column with import path of its package
+type goType struct {
+	name       string
+	importPath string
+	comparable bool
+}
+
+var (
+	typeInt16       = goType{name: "int16", comparable: true}
+	typeInt32       = goType{name: "int32", comparable: true}
+	typeInt64       = goType{name: "int64", comparable: true}
+	typeFloat64     = goType{name: "float64", comparable: true}
+	typeBool        = goType{name: "bool", comparable: true}
+	typeString      = goType{name: "string", comparable: true}
+	typeBytes       = goType{name: "[]byte"}
+	typeTime        = goType{name: "time.Time", importPath: "time", comparable: true}
+	typeDecimal     = goType{name: "decimal.Decimal", importPath: "github.com/shopspring/decimal", comparable: true}
+	typeAny         = goType{name: "any", comparable: true}
+	typeNullInt16   = goType{name: "sql.NullInt16", importPath: "database/sql", comparable: true}
+	typeNullInt32   = goType{name: "sql.NullInt32", importPath: "database/sql", comparable: true}
+	typeNullInt64   = goType{name: "sql.NullInt64", importPath: "database/sql", comparable: true}
+	typeNullFloat64 = goType{name: "sql.NullFloat64", importPath: "database/sql", comparable: true}
+	typeNullBool    = goType{name: "sql.NullBool", importPath: "database/sql", comparable: true}
+	typeNullString  = goType{name: "sql.NullString", importPath: "database/sql", comparable: true}
+	typeNullTime    = goType{name: "sql.NullTime", importPath: "database/sql", comparable: true}
+	typeNullDecimal = goType{name: "decimal.NullDecimal", importPath: "github.com/shopspring/decimal", comparable: true}
+)
+
+// comparableTypes are comparable types of packages which may be used as column types
+var comparableTypes = []goType{
+	typeTime, typeDecimal, typeNullDecimal, typeNullInt16, typeNullInt32, typeNullInt64,
+	typeNullFloat64, typeNullBool, typeNullString, typeNullTime,
+}
+
+// comparableBuiltins are predeclared comparable types
+var comparableBuiltins = map[string]bool{
+	"bool": true, "string": true, "int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"byte": true, "rune": true, "float32": true, "float64": true, "complex64": true, "complex128": true,
+	"any": true, "error": true,
+}
+
+// nullTypes are types of nullable columns, they have helpers in null.go of ondatra
+var nullTypes = map[goType]goType{
+	typeInt16:   typeNullInt16,
+	typeInt32:   typeNullInt32,
+	typeInt64:   typeNullInt64,
+	typeFloat64: typeNullFloat64,
+	typeBool:    typeNullBool,
+	typeString:  typeNullString,
+	typeTime:    typeNullTime,
+	typeDecimal: typeNullDecimal,
+}
+
+// columnType returns type of Column[T], types which are not comparable are any
+func (t goType) columnType() string {
+	if !t.comparable {
+		return "any"
+	}
+	return t.name
+}
+
+// goTypeOf returns Go type of database type, unknown types are any
+func goTypeOf(dialect ondatra.Dialect, dbType string, nullable bool) goType {
+	t := baseGoType(dialect, dbType)
+	if nullable {
+		if nullType, ok := nullTypes[t]; ok {
+			return nullType
+		}
+	}
+	return t
+}
+
+func baseGoType(dialect ondatra.Dialect, dbType string) goType {
+	dbType = strings.ToLower(dbType)
+	if strings.HasPrefix(dbType, "tinyint(1)") {
+		return typeBool
+	}
+
+	// size and precision in parentheses, unsigned and zerofill do not change the base type
+	var b strings.Builder
+	depth := 0
+	for _, r := range dbType {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	words := strings.Fields(b.String())
+	unsigned := slices.Contains(words, "unsigned")
+	words = slices.DeleteFunc(words, func(word string) bool {
+		return word == "unsigned" || word == "zerofill"
+	})
+	dbType = strings.Join(words, " ")
+
+	switch dbType {
+	case "smallint", "int2", "smallserial", "tinyint", "year":
+		if unsigned {
+			return typeInt32
+		}
+		return typeInt16
+	case "integer", "int", "int4", "serial", "mediumint":
+		if dialect == ondatra.DialectSQLite || unsigned {
+			return typeInt64
+		}
+		return typeInt32
+	case "bigint", "int8", "bigserial":
+		return typeInt64
+	case "real", "float", "float4", "float8", "double", "double precision":
+		return typeFloat64
+	case "numeric", "decimal", "money":
+		return typeDecimal
+	case "boolean", "bool":
+		return typeBool
+	case "text", "varchar", "char", "character", "character varying", "nvarchar", "nchar", "clob",
+		"tinytext", "mediumtext", "longtext", "uuid", "citext", "enum", "set", "inet", "cidr", "macaddr",
+		"interval", "time", "time without time zone", "time with time zone", "xml":
+		return typeString
+	case "json", "jsonb", "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return typeBytes
+	case "date", "datetime", "timestamp", "timestamptz", "timestamp without time zone", "timestamp with time zone":
+		return typeTime
+	default:
+		return typeAny
+	}
+}
+
+// commonInitialisms are upper cased in Go names
+var commonInitialisms = map[string]bool{
+	"id": true, "ids": true, "uuid": true, "url": true, "uri": true, "ip": true, "api": true, "sql": true,
+	"json": true, "xml": true, "html": true, "http": true, "https": true, "sku": true, "utc": true,
+}
+
+// pascalCase returns exported Go name of snake case identifier, e.g. user_id is UserID
+func pascalCase(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		if commonInitialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	result := b.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		result = "T" + result
+	}
+	return result
+}
+
+// snakeCase returns snake case of Go name, e.g. UserID is user_id
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			previousLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if previousLower || nextLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// singular returns naive singular form of English plural name, e.g. Categories is Category
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "shes"), strings.HasSuffix(name, "ches"),
+		strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "uses"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "ss"), strings.HasSuffix(name, "us"), strings.HasSuffix(name, "is"):
+		return name
+	case strings.HasSuffix(name, "s") && len(name) > 1:
+		return name[:len(name)-1]
+	default:
+		return name
+	}
+}
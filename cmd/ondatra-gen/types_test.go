@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stepanbukhtii/ondatra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoTypeOf(t *testing.T) {
+	var tests = []struct {
+		dialect  ondatra.Dialect
+		dbType   string
+		nullable bool
+		expected goType
+	}{
+		{ondatra.DialectPostgres, "integer", false, typeInt32},
+		{ondatra.DialectPostgres, "integer", true, typeNullInt32},
+		{ondatra.DialectSQLite, "INTEGER", false, typeInt64},
+		{ondatra.DialectPostgres, "bigint", true, typeNullInt64},
+		{ondatra.DialectPostgres, "smallint", false, typeInt16},
+		{ondatra.DialectMySQL, "int(10) unsigned", false, typeInt64},
+		{ondatra.DialectMySQL, "tinyint(1)", true, typeNullBool},
+		{ondatra.DialectPostgres, "character varying", true, typeNullString},
+		{ondatra.DialectSQLite, "VARCHAR(255)", false, typeString},
+		{ondatra.DialectPostgres, "numeric", true, typeNullDecimal},
+		{ondatra.DialectSQLite, "DECIMAL(10, 2)", false, typeDecimal},
+		{ondatra.DialectPostgres, "double precision", false, typeFloat64},
+		{ondatra.DialectPostgres, "timestamp with time zone", true, typeNullTime},
+		{ondatra.DialectMySQL, "datetime(6)", false, typeTime},
+		{ondatra.DialectPostgres, "jsonb", true, typeBytes},
+		{ondatra.DialectPostgres, "_text", false, typeAny},
+	}
+
+	for _, test := range tests {
+		t.Run(test.dbType, func(t *testing.T) {
+			assert.Equal(t, test.expected, goTypeOf(test.dialect, test.dbType, test.nullable))
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	var tests = []struct {
+		name     string
+		expected string
+		actual   string
+	}{
+		{name: "pascal case", expected: "UserID", actual: pascalCase("user_id")},
+		{name: "pascal case initialism", expected: "APIKeyURL", actual: pascalCase("api_key_url")},
+		{name: "pascal case digit", expected: "T2fa", actual: pascalCase("2fa")},
+		{name: "snake case", expected: "user_id", actual: snakeCase("UserID")},
+		{name: "snake case initialism", expected: "http_server", actual: snakeCase("HTTPServer")},
+		{name: "singular", expected: "User", actual: singular("Users")},
+		{name: "singular ies", expected: "Category", actual: singular("Categories")},
+		{name: "singular es", expected: "Address", actual: singular("Addresses")},
+		{name: "singular status", expected: "Status", actual: singular("Status")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.actual)
+		})
+	}
+}
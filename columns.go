@@ -36,6 +36,11 @@ func NewColumn[T comparable](table, column string) Column[T] {
 	}
 }
 
+// ToSQL returns qualified column name, so a column can be used as Expr
+func (c Column[T]) ToSQL() (string, []any, error) {
+	return c.QualifiedName, nil, nil
+}
+
 func (c Column[T]) IN(value ...T) Expr {
 	return NewExpr(
 		fmt.Sprintf("%s IN (%s)", c.QualifiedName, strings.TrimRight(strings.Repeat("?,", len(value)), ",")),
@@ -50,6 +55,16 @@ func (c Column[T]) NIN(value ...T) Expr {
 	)
 }
 
+// InQuery compare column with values returned by subquery
+func (c Column[T]) InQuery(b Builder) Expr {
+	return NewExpr(fmt.Sprintf("%s IN (?)", c.QualifiedName), b)
+}
+
+// NotInQuery compare column with values returned by subquery
+func (c Column[T]) NotInQuery(b Builder) Expr {
+	return NewExpr(fmt.Sprintf("%s NOT IN (?)", c.QualifiedName), b)
+}
+
 func (c Column[T]) IsNull() Expr {
 	return NewExpr(fmt.Sprintf("%s IS NULL", c.QualifiedName))
 }
@@ -103,6 +118,11 @@ func (v Value[T]) Ptr(ptr *T) Expr {
 	return NewExpr(string(v), *ptr)
 }
 
+// Query compare with scalar subquery, e.g. col > (SELECT ...)
+func (v Value[T]) Query(b Builder) Expr {
+	return NewExpr(string(v), Subquery(b))
+}
+
 type SetValue[T comparable] string
 
 func (v SetValue[T]) Value(value T) Expr {
@@ -113,46 +133,58 @@ func (v SetValue[T]) Ptr(ptr *T) Expr {
 	return NewExpr(string(v), *ptr)
 }
 
+// Query set value returned by scalar subquery
+func (v SetValue[T]) Query(b Builder) Expr {
+	return NewExpr(string(v), Subquery(b))
+}
+
 func (v SetValue[T]) Null() Expr {
 	return NewExpr(string(v), nil)
 }
 
+// OR returns conditions joined by OR in parentheses, nil conditions are skipped
 func OR(conditions ...Expr) Expr {
-	var rawSQLs []string
-	var args []any
-	for i := range conditions {
-		if conditions[i] == nil {
-			continue
-		}
-
-		rawSQl, sqlArgs, err := conditions[i].ToSQL()
-		if err != nil {
-			return nil
-		}
+	return conditionsExpr{operator: " OR ", conditions: conditions}
+}
 
-		rawSQLs = append(rawSQLs, rawSQl)
-		args = append(args, sqlArgs...)
-	}
+// AND returns conditions joined by AND in parentheses, nil conditions are skipped
+func AND(conditions ...Expr) Expr {
+	return conditionsExpr{operator: " AND ", conditions: conditions}
+}
 
-	return NewExpr(fmt.Sprintf("(%s)", strings.Join(rawSQLs, " OR ")), args...)
+// conditionsExpr is rendered with the statement, so subqueries of its conditions get tenant and policies
+type conditionsExpr struct {
+	operator   string
+	conditions []Expr
 }
 
-func AND(conditions ...Expr) Expr {
+func (c conditionsExpr) ToSQL() (string, []any, error) {
 	var rawSQLs []string
 	var args []any
-	for i := range conditions {
-		if conditions[i] == nil {
+	for i := range c.conditions {
+		if c.conditions[i] == nil {
 			continue
 		}
 
-		rawSQl, sqlArgs, err := conditions[i].ToSQL()
+		rawSQL, sqlArgs, err := c.conditions[i].ToSQL()
 		if err != nil {
-			return nil
+			return "", nil, err
 		}
 
-		rawSQLs = append(rawSQLs, rawSQl)
+		rawSQLs = append(rawSQLs, rawSQL)
 		args = append(args, sqlArgs...)
 	}
 
-	return NewExpr(fmt.Sprintf("(%s)", strings.Join(rawSQLs, " AND ")), args...)
+	return fmt.Sprintf("(%s)", strings.Join(rawSQLs, c.operator)), args, nil
+}
+
+func (c conditionsExpr) bindSubqueries(bind func(Builder) Builder) Expr {
+	conditions := make([]Expr, 0, len(c.conditions))
+	for i := range c.conditions {
+		if c.conditions[i] != nil {
+			conditions = append(conditions, bindSubqueries(c.conditions[i], bind))
+		}
+	}
+	c.conditions = conditions
+	return c
 }
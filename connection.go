@@ -3,6 +3,7 @@ package ondatra
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -29,18 +30,30 @@ func NewDB(db *sqlx.DB) Connection {
 	return &DB{DB: db}
 }
 
+// Rebind replaces placeholders skipping string literals, quoted identifiers and comments
+func (c *DB) Rebind(query string) string {
+	return rebind(sqlx.BindType(c.DriverName()), query)
+}
+
 func (c *DB) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
 	return c.BeginTxx(ctx, nil)
 }
 
 type Tx struct {
 	*sqlx.Tx
+	// driver is driver of the database of the transaction, it is set by RunInTransaction
+	driver driver.Driver
 }
 
 func NewTx(tx *sqlx.Tx) Connection {
 	return &Tx{Tx: tx}
 }
 
+// Rebind replaces placeholders skipping string literals, quoted identifiers and comments
+func (c *Tx) Rebind(query string) string {
+	return rebind(sqlx.BindType(c.DriverName()), query)
+}
+
 func (c *Tx) BeginTx(_ context.Context) (*sqlx.Tx, error) {
 	return nil, ErrAlreadyInTransaction
 }
@@ -0,0 +1,455 @@
+package ondatra
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// copyInDrivers are packages of drivers which implement COPY FROM STDIN by prepared statement, e.g. lib/pq.
+// Drivers are detected by type because other postgres drivers, e.g. pgx stdlib, may be registered as postgres.
+var copyInDrivers = map[string]bool{
+	"github.com/lib/pq": true,
+	"github.com/GoogleCloudPlatform/cloudsql-proxy/proxy/dialers/postgres": true,
+}
+
+// CopySource is an iterator of rows of CopyFrom
+type CopySource interface {
+	// Next advances to the next row, it returns false after the last row or on error
+	Next() bool
+	// Values returns values of the current row in order of columns
+	Values() ([]any, error)
+	// Err returns error which stopped the iteration
+	Err() error
+}
+
+// structCopySource is a source of structs which resolves values of columns by db tags
+type structCopySource interface {
+	CopySource
+	Columns() []string
+	bindColumns(columns []string) error
+}
+
+type rowsSource struct {
+	rows [][]any
+	next int
+}
+
+// CopyFromRows returns source of rows
+func CopyFromRows(rows [][]any) CopySource {
+	return &rowsSource{rows: rows}
+}
+
+func (s *rowsSource) Next() bool {
+	if s.next >= len(s.rows) {
+		return false
+	}
+	s.next++
+	return true
+}
+
+func (s *rowsSource) Values() ([]any, error) {
+	return s.rows[s.next-1], nil
+}
+
+func (s *rowsSource) Err() error {
+	return nil
+}
+
+type funcSource struct {
+	next func() ([]any, bool, error)
+	row  []any
+	err  error
+}
+
+// CopyFromFunc returns source of rows returned by next until it returns false
+func CopyFromFunc(next func() ([]any, bool, error)) CopySource {
+	return &funcSource{next: next}
+}
+
+func (s *funcSource) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	var ok bool
+	s.row, ok, s.err = s.next()
+	return ok && s.err == nil
+}
+
+func (s *funcSource) Values() ([]any, error) {
+	return s.row, nil
+}
+
+func (s *funcSource) Err() error {
+	return s.err
+}
+
+type structSource struct {
+	structType reflect.Type
+	next       func() (reflect.Value, bool, error)
+	indexes    []int
+	row        reflect.Value
+	err        error
+}
+
+// CopyFromStructs returns source of a slice of structs with db tags, columns are fields tagged as column
+// by the rules of StructColumns, columns tagged as default are copied only when they are passed to CopyFrom
+func CopyFromStructs(rows any) CopySource {
+	v := reflect.Indirect(reflect.ValueOf(rows))
+	if v.Kind() != reflect.Slice {
+		return &structSource{err: ErrRowsNotSlice}
+	}
+
+	var i int
+	return newStructSource(v.Type().Elem(), func() (reflect.Value, bool, error) {
+		if i >= v.Len() {
+			return reflect.Value{}, false, nil
+		}
+		i++
+		return v.Index(i - 1), true, nil
+	})
+}
+
+// CopyFromStructFunc returns source of structs with db tags returned by next until it returns false
+func CopyFromStructFunc[T any](next func() (T, bool, error)) CopySource {
+	return newStructSource(reflect.TypeOf((*T)(nil)).Elem(), func() (reflect.Value, bool, error) {
+		row, ok, err := next()
+		return reflect.ValueOf(row), ok, err
+	})
+}
+
+func newStructSource(t reflect.Type, next func() (reflect.Value, bool, error)) *structSource {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return &structSource{err: ErrRowsNotSlice}
+	}
+	return &structSource{structType: t, next: next}
+}
+
+// Columns returns columns tagged as column without default tag
+func (s *structSource) Columns() []string {
+	if s.structType == nil {
+		return nil
+	}
+	var columns []string
+	for i := 0; i < s.structType.NumField(); i++ {
+		dbTags := strings.Split(s.structType.Field(i).Tag.Get("db"), ",")
+		if slices.Contains(dbTags, modelTagColumn) && !slices.Contains(dbTags, modelTagDefault) {
+			columns = append(columns, dbTags[0])
+		}
+	}
+	return columns
+}
+
+func (s *structSource) bindColumns(columns []string) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	fields := make(map[string]int)
+	for i := 0; i < s.structType.NumField(); i++ {
+		dbTags := strings.Split(s.structType.Field(i).Tag.Get("db"), ",")
+		if slices.Contains(dbTags, modelTagColumn) {
+			fields[dbTags[0]] = i
+		}
+	}
+
+	s.indexes = make([]int, len(columns))
+	for i, column := range columns {
+		index, ok := fields[column]
+		if !ok {
+			return fmt.Errorf("%w: column %s is not a field of %s", ErrCopyValues, column, s.structType)
+		}
+		s.indexes[i] = index
+	}
+	return nil
+}
+
+func (s *structSource) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	var ok bool
+	s.row, ok, s.err = s.next()
+	if !ok || s.err != nil {
+		return false
+	}
+	if s.row.Kind() == reflect.Pointer {
+		if s.row.IsNil() {
+			s.err = fmt.Errorf("%w: row is nil", ErrCopyValues)
+			return false
+		}
+		s.row = s.row.Elem()
+	}
+	return true
+}
+
+func (s *structSource) Values() ([]any, error) {
+	values := make([]any, len(s.indexes))
+	for i, index := range s.indexes {
+		values[i] = s.row.Field(index).Interface()
+	}
+	return values, nil
+}
+
+func (s *structSource) Err() error {
+	return s.err
+}
+
+// Copy loads rows into a table, it is created by Builder.CopyFrom
+type Copy struct {
+	builder       Builder
+	table         string
+	columns       []string
+	source        CopySource
+	batchSize     int
+	progressEvery int64
+	progress      func(copied int64)
+}
+
+// CopyFrom loads rows of source into table by COPY FROM STDIN of lib/pq driver, other drivers
+// insert rows by multi-row INSERT statements. Rows are loaded in the current transaction or a new one.
+// Columns may be empty for source of structs.
+func (b Builder) CopyFrom(table string, columns []string, source CopySource) Copy {
+	return Copy{
+		builder: b,
+		table:   table,
+		columns: columns,
+		source:  source,
+	}
+}
+
+// BatchSize set number of rows of INSERT statement when COPY is not supported,
+// by default it is limited by the parameters limit of the dialect
+func (c Copy) BatchSize(rows int) Copy {
+	c.batchSize = rows
+	return c
+}
+
+// Progress set callback which is called with number of copied rows after every `every` rows and after the last row
+func (c Copy) Progress(every int64, progress func(copied int64)) Copy {
+	c.progressEvery = max(1, every)
+	c.progress = progress
+	return c
+}
+
+// ExecContext copies rows and returns number of copied rows
+func (c Copy) ExecContext(ctx context.Context) (int64, error) {
+	columns := c.columns
+	if structSource, ok := c.source.(structCopySource); ok {
+		if len(columns) == 0 {
+			columns = structSource.Columns()
+		}
+		if err := structSource.bindColumns(columns); err != nil {
+			return 0, err
+		}
+	}
+	if len(columns) == 0 {
+		return 0, NotSetColumns
+	}
+
+	b := c.builder.tenantFromContext(ctx)
+	if b.policies != nil {
+		if _, err := b.policies.Conditions(ctx, c.table, CommandInsert); err != nil {
+			return 0, err
+		}
+	}
+
+	tenantIndex := -1
+	if b.tenantGuard != nil {
+		if column, ok := b.tenantGuard.Column(c.table); ok {
+			if b.tenantID == nil {
+				return 0, TenantError{Table: c.table, Command: CommandInsert}
+			}
+			if tenantIndex = slices.Index(columns, column); tenantIndex < 0 {
+				tenantIndex = len(columns)
+				columns = append(slices.Clone(columns), column)
+			}
+		}
+	}
+
+	rows := copyRows{
+		source:      c.source,
+		columns:     len(columns),
+		tenantIndex: tenantIndex,
+		tenantID:    b.tenantID,
+	}
+
+	var copied int64
+	err := b.transaction(ctx, func(tx Builder) error {
+		var err error
+		if isCopyInDriver(connDriver(tx.writerConn)) {
+			copied, err = c.copyIn(ctx, tx, columns, &rows)
+		} else {
+			copied, err = c.insert(ctx, tx, columns, &rows)
+		}
+		return err
+	})
+	return copied, err
+}
+
+// copyIn sends rows by prepared COPY FROM STDIN statement of lib/pq
+func (c Copy) copyIn(ctx context.Context, tx Builder, columns []string, rows *copyRows) (int64, error) {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = DialectPostgres.QuoteIdent(column)
+	}
+	query := fmt.Sprintf("COPY %s (%s) FROM STDIN", DialectPostgres.QuoteIdent(c.table), strings.Join(quoted, ", "))
+	if DebugMode {
+		log.Println("Query:", query)
+	}
+
+	stmt, err := sqlxTx(tx.writerConn).PrepareContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var copied int64
+	for {
+		values, ok, err := rows.next()
+		if err != nil {
+			return copied, err
+		}
+		if !ok {
+			break
+		}
+		if _, err = stmt.ExecContext(ctx, values...); err != nil {
+			return copied, fmt.Errorf("copy row %d: %w", copied, err)
+		}
+		copied++
+		if c.progress != nil && copied%c.progressEvery == 0 {
+			c.progress(copied)
+		}
+	}
+
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		return copied, err
+	}
+	if c.progress != nil && copied%c.progressEvery != 0 {
+		c.progress(copied)
+	}
+	return copied, stmt.Close()
+}
+
+// insert sends rows by multi-row INSERT statements
+func (c Copy) insert(ctx context.Context, tx Builder, columns []string, rows *copyRows) (int64, error) {
+	batchSize := c.batchSize
+	if batchSize <= 0 {
+		batchSize = max(1, tx.dialect.MaxParameters()/len(columns))
+	}
+
+	var copied, reported int64
+	for done := false; !done; {
+		statement := tx.Insert().Into(c.table).Columns(columns...)
+		var count int
+		for count < batchSize {
+			values, ok, err := rows.next()
+			if err != nil {
+				return copied, err
+			}
+			if !ok {
+				done = true
+				break
+			}
+			statement = statement.Values(values...)
+			count++
+		}
+		if count == 0 {
+			break
+		}
+
+		if _, err := statement.ExecContext(ctx); err != nil {
+			return copied, fmt.Errorf("copy rows %d-%d: %w", copied, copied+int64(count)-1, err)
+		}
+		copied += int64(count)
+
+		if c.progress != nil && copied/c.progressEvery > reported/c.progressEvery {
+			c.progress(copied)
+			reported = copied
+		}
+	}
+	if c.progress != nil && copied != reported {
+		c.progress(copied)
+	}
+	return copied, nil
+}
+
+// copyRows reads rows of source and sets tenant of the rows
+type copyRows struct {
+	source      CopySource
+	columns     int
+	tenantIndex int
+	tenantID    any
+	read        int64
+}
+
+func (r *copyRows) next() ([]any, bool, error) {
+	if !r.source.Next() {
+		return nil, false, r.source.Err()
+	}
+	values, err := r.source.Values()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if r.tenantIndex >= 0 {
+		values = slices.Clone(values)
+		if r.tenantIndex < len(values) {
+			values[r.tenantIndex] = r.tenantID
+		} else {
+			values = append(values, r.tenantID)
+		}
+	}
+	if len(values) != r.columns {
+		return nil, false, fmt.Errorf("%w: row %d has %d values for %d columns", ErrCopyValues, r.read, len(values), r.columns)
+	}
+	r.read++
+	return values, true, nil
+}
+
+// connDriver returns driver of the connection, it is nil for transactions which are not begun by the builder
+func connDriver(conn Connection) driver.Driver {
+	switch c := conn.(type) {
+	case *DB:
+		return c.Driver()
+	case *Tx:
+		return c.driver
+	case *CachedDB:
+		return c.db.Driver()
+	case *CachedTx:
+		return c.tx.driver
+	default:
+		return nil
+	}
+}
+
+func isCopyInDriver(d driver.Driver) bool {
+	if d == nil {
+		return false
+	}
+	t := reflect.TypeOf(d)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return copyInDrivers[t.PkgPath()]
+}
+
+func sqlxTx(conn Connection) *sqlx.Tx {
+	switch c := conn.(type) {
+	case *Tx:
+		return c.Tx
+	case *CachedTx:
+		return c.tx.Tx
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,184 @@
+package ondatra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type copyUser struct {
+	ID       int64  `db:"id,column,pk,default"`
+	TenantID int64  `db:"tenant_id,column"`
+	Name     string `db:"name,column"`
+	Email    string `db:"email"`
+}
+
+func TestCopySource(t *testing.T) {
+	var tests = []struct {
+		name         string
+		source       CopySource
+		columns      []string
+		expectValues [][]any
+		expectErr    error
+	}{
+		{
+			name:         "rows",
+			source:       CopyFromRows([][]any{{1, "a"}, {2, "b"}}),
+			expectValues: [][]any{{1, "a"}, {2, "b"}},
+		}, {
+			name: "func",
+			source: CopyFromFunc(func() func() ([]any, bool, error) {
+				var i int
+				return func() ([]any, bool, error) {
+					i++
+					return []any{i}, i <= 2, nil
+				}
+			}()),
+			expectValues: [][]any{{1}, {2}},
+		}, {
+			name:         "structs",
+			source:       CopyFromStructs([]*copyUser{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}),
+			columns:      []string{"id", "name"},
+			expectValues: [][]any{{int64(1), "a"}, {int64(2), "b"}},
+		}, {
+			name:      "structs unknown column",
+			source:    CopyFromStructs([]copyUser{{Name: "a"}}),
+			columns:   []string{"email"},
+			expectErr: ErrCopyValues,
+		}, {
+			name:      "not slice",
+			source:    CopyFromStructs(copyUser{}),
+			columns:   []string{"name"},
+			expectErr: ErrRowsNotSlice,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if source, ok := test.source.(structCopySource); ok {
+				err := source.bindColumns(test.columns)
+				if test.expectErr != nil {
+					assert.ErrorIs(t, err, test.expectErr)
+					return
+				}
+				require.NoError(t, err)
+			}
+
+			var values [][]any
+			for test.source.Next() {
+				row, err := test.source.Values()
+				require.NoError(t, err)
+				values = append(values, row)
+			}
+			assert.NoError(t, test.source.Err())
+			assert.Equal(t, test.expectValues, values)
+		})
+	}
+
+	assert.Equal(t, []string{"tenant_id", "name"}, CopyFromStructs([]copyUser{}).(structCopySource).Columns())
+}
+
+// newCopyInMockBuilder returns mock builder which sends rows by COPY FROM STDIN like lib/pq
+func newCopyInMockBuilder(t *testing.T) (Builder, sqlmock.Sqlmock) {
+	copyInDrivers["github.com/DATA-DOG/go-sqlmock"] = true
+	t.Cleanup(func() { delete(copyInDrivers, "github.com/DATA-DOG/go-sqlmock") })
+	return newMockBuilder(t)
+}
+
+func TestBuilder_CopyFrom(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("copy in", func(t *testing.T) {
+		b, mock := newCopyInMockBuilder(t)
+		mock.ExpectBegin()
+		prepare := mock.ExpectPrepare(`COPY "users" ("id", "name") FROM STDIN`)
+		prepare.ExpectExec().WithArgs(1, "a").WillReturnResult(sqlmock.NewResult(0, 0))
+		prepare.ExpectExec().WithArgs(2, "b").WillReturnResult(sqlmock.NewResult(0, 0))
+		prepare.ExpectExec().WithArgs(3, "c").WillReturnResult(sqlmock.NewResult(0, 0))
+		prepare.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectCommit()
+
+		var progress []int64
+		copied, err := b.CopyFrom("users", []string{"id", "name"}, CopyFromRows([][]any{{1, "a"}, {2, "b"}, {3, "c"}})).
+			Progress(2, func(copied int64) { progress = append(progress, copied) }).
+			ExecContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), copied)
+		assert.Equal(t, []int64{2, 3}, progress)
+	})
+
+	t.Run("copy in transaction with tenant", func(t *testing.T) {
+		b, mock := newCopyInMockBuilder(t)
+		guard := NewTenantGuard()
+		guard.Register(NewTable("users", nil))
+
+		mock.ExpectBegin()
+		prepare := mock.ExpectPrepare(`COPY "users" ("tenant_id", "name") FROM STDIN`)
+		prepare.ExpectExec().WithArgs(7, "a").WillReturnResult(sqlmock.NewResult(0, 0))
+		prepare.ExpectExec().WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := b.TenantGuard(guard).Tenant(7).RunInTransaction(ctx, func(tx Builder) error {
+			copied, err := tx.CopyFrom("users", nil, CopyFromStructs([]copyUser{{TenantID: 1, Name: "a"}})).ExecContext(ctx)
+			assert.Equal(t, int64(1), copied)
+			return err
+		})
+		assert.NoError(t, err)
+
+		_, err = b.TenantGuard(guard).CopyFrom("users", nil, CopyFromStructs([]copyUser{})).ExecContext(ctx)
+		assert.ErrorIs(t, err, ErrTenantNotSet)
+	})
+
+	t.Run("insert fallback", func(t *testing.T) {
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		defer db.Close()
+		b := NewBuilder(sqlx.NewDb(db, "mysql"))
+
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO users (id, name) VALUES (?,?),(?,?)").WithArgs(1, "a", 2, "b").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO users (id, name) VALUES (?,?)").WithArgs(3, "c").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		var progress []int64
+		copied, err := b.CopyFrom("users", []string{"id", "name"}, CopyFromRows([][]any{{1, "a"}, {2, "b"}, {3, "c"}})).
+			BatchSize(2).
+			Progress(1, func(copied int64) { progress = append(progress, copied) }).
+			ExecContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), copied)
+		assert.Equal(t, []int64{2, 3}, progress)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("postgres driver without copy in", func(t *testing.T) {
+		b, mock := newMockBuilder(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO users (id, name) VALUES ($1,$2)").WithArgs(1, "a").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		copied, err := b.CopyFrom("users", []string{"id", "name"}, CopyFromRows([][]any{{1, "a"}})).ExecContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), copied)
+	})
+
+	t.Run("values do not match columns", func(t *testing.T) {
+		b, mock := newCopyInMockBuilder(t)
+		mock.ExpectBegin()
+		mock.ExpectPrepare(`COPY "users" ("id", "name") FROM STDIN`).WillBeClosed()
+		mock.ExpectRollback()
+
+		_, err := b.CopyFrom("users", []string{"id", "name"}, CopyFromRows([][]any{{1}})).ExecContext(ctx)
+		assert.ErrorIs(t, err, ErrCopyValues)
+
+		_, err = b.CopyFrom("users", nil, CopyFromRows(nil)).ExecContext(ctx)
+		assert.ErrorIs(t, err, NotSetColumns)
+	})
+}
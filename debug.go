@@ -0,0 +1,19 @@
+package ondatra
+
+// DebugSQL is a statement with inlined arguments. It is a distinct type so it cannot be
+// passed to the query methods by mistake, use it only for logs and manual sessions.
+type DebugSQL string
+
+func (s DebugSQL) String() string {
+	return string(s)
+}
+
+// ToDebugSQL returns copy-pasteable statement with arguments inlined as literals of the builder dialect.
+// Values are quoted for reading only, the result is not safe against injection and must never be executed.
+func (b Builder) ToDebugSQL() (DebugSQL, error) {
+	query, args, err := b.ToSQL()
+	if err != nil {
+		return "", err
+	}
+	return DebugSQL(inlineArgs(query, args, b.dialect)), nil
+}
@@ -0,0 +1,51 @@
+package ondatra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuilder_ToDebugSQL(t *testing.T) {
+	var tests = []struct {
+		name    string
+		builder Builder
+		expect  DebugSQL
+	}{
+		{
+			name: "question",
+			builder: NewEmptyBuilder().
+				Dialect(DialectMySQL).
+				Select("a").
+				From("t").
+				Where("b = ? AND c = 'x?' AND d IN (?,?)", "it's", 1, nil),
+			expect: "SELECT a FROM t WHERE b = 'it''s' AND c = 'x?' AND d IN (1,NULL)",
+		}, {
+			name: "dollar",
+			builder: NewEmptyBuilder().
+				Dialect(DialectPostgres).
+				Update().
+				Table("t").
+				Set("a", []byte("ab")).
+				Where("id = ?", 1).
+				PlaceholderFormat(Dollar),
+			expect: `UPDATE t SET a = '\x6162' WHERE id = 1`,
+		}, {
+			name: "named",
+			builder: NewEmptyBuilder().
+				Dialect(DialectSQLServer).
+				Delete().
+				From("t").
+				Where("a = ? AND b = ?", true, "x").
+				PlaceholderFormat(NamedAt),
+			expect: "DELETE FROM t WHERE a = 1 AND b = N'x'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := test.builder.ToDebugSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, query)
+		})
+	}
+}
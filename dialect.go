@@ -0,0 +1,148 @@
+package ondatra
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Dialect is a SQL dialect of the database, zero value renders standard SQL
+type Dialect string
+
+const (
+	DialectPostgres  Dialect = "postgres"
+	DialectMySQL     Dialect = "mysql"
+	DialectSQLite    Dialect = "sqlite"
+	DialectSQLServer Dialect = "sqlserver"
+)
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// DialectByDriver returns dialect for the database/sql driver name
+func DialectByDriver(driverName string) Dialect {
+	switch driverName {
+	case "postgres", "pgx", "pgx/v4", "pgx/v5", "cloudsqlpostgres", "nrpostgres":
+		return DialectPostgres
+	case "mysql", "nrmysql":
+		return DialectMySQL
+	case "sqlite3", "sqlite":
+		return DialectSQLite
+	case "sqlserver", "mssql", "azuresql":
+		return DialectSQLServer
+	default:
+		return ""
+	}
+}
+
+// MaxParameters returns maximum number of bind parameters in one statement
+func (d Dialect) MaxParameters() int {
+	switch d {
+	case DialectSQLite:
+		return 32766
+	case DialectSQLServer:
+		return 2100
+	default:
+		return 65535
+	}
+}
+
+// Literal renders value as SQL literal of the dialect, use it only for logging
+func (d Dialect) Literal(value any) string {
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "NULL"
+		}
+		if _, ok := value.(driver.Valuer); !ok || rv.Elem().Type().Implements(valuerType) {
+			return d.Literal(rv.Elem().Interface())
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case sql.NamedArg:
+		return d.Literal(v.Value)
+	case decimal.Decimal:
+		return v.String()
+	case decimal.NullDecimal:
+		if !v.Valid {
+			return "NULL"
+		}
+		return v.Decimal.String()
+	case driver.Valuer:
+		resolved, err := v.Value()
+		if err != nil {
+			return d.quote(fmt.Sprintf("%v", v))
+		}
+		return d.Literal(resolved)
+	case string:
+		return d.quote(v)
+	case []byte:
+		if v == nil {
+			return "NULL"
+		}
+		return d.bytes(v)
+	case time.Time:
+		return d.time(v)
+	case bool:
+		return d.bool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	return d.quote(fmt.Sprintf("%v", value))
+}
+
+func (d Dialect) quote(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if d == DialectMySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	if d == DialectSQLServer {
+		return "N'" + s + "'"
+	}
+	return "'" + s + "'"
+}
+
+func (d Dialect) bytes(b []byte) string {
+	switch d {
+	case DialectPostgres:
+		return `'\x` + hex.EncodeToString(b) + "'"
+	case DialectSQLServer:
+		return "0x" + hex.EncodeToString(b)
+	default:
+		return "X'" + hex.EncodeToString(b) + "'"
+	}
+}
+
+func (d Dialect) time(t time.Time) string {
+	switch d {
+	case DialectMySQL, DialectSQLServer:
+		return "'" + t.Format("2006-01-02 15:04:05.999999") + "'"
+	default:
+		return "'" + t.Format(time.RFC3339Nano) + "'"
+	}
+}
+
+func (d Dialect) bool(b bool) string {
+	switch d {
+	case DialectSQLite, DialectSQLServer:
+		if b {
+			return "1"
+		}
+		return "0"
+	default:
+		return strings.ToUpper(strconv.FormatBool(b))
+	}
+}
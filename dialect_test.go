@@ -0,0 +1,49 @@
+package ondatra
+
+import (
+	"database/sql"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDialect_Literal(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	text := "it's"
+	var nilText *string
+
+	var tests = []struct {
+		name    string
+		dialect Dialect
+		value   any
+		expect  string
+	}{
+		{name: "nil", dialect: DialectPostgres, value: nil, expect: "NULL"},
+		{name: "nil pointer", dialect: DialectPostgres, value: nilText, expect: "NULL"},
+		{name: "pointer", dialect: DialectPostgres, value: &text, expect: "'it''s'"},
+		{name: "string", dialect: DialectPostgres, value: `it's \n`, expect: `'it''s \n'`},
+		{name: "mysql string", dialect: DialectMySQL, value: `it's \n`, expect: `'it''s \\n'`},
+		{name: "sqlserver string", dialect: DialectSQLServer, value: "it's", expect: "N'it''s'"},
+		{name: "int", dialect: DialectPostgres, value: int64(-12), expect: "-12"},
+		{name: "float", dialect: DialectPostgres, value: 1.25, expect: "1.25"},
+		{name: "bool", dialect: DialectPostgres, value: true, expect: "TRUE"},
+		{name: "sqlite bool", dialect: DialectSQLite, value: false, expect: "0"},
+		{name: "time", dialect: DialectPostgres, value: date, expect: "'2024-01-02T03:04:05.6Z'"},
+		{name: "mysql time", dialect: DialectMySQL, value: date, expect: "'2024-01-02 03:04:05.6'"},
+		{name: "bytes", dialect: DialectPostgres, value: []byte{0xde, 0xad}, expect: `'\xdead'`},
+		{name: "mysql bytes", dialect: DialectMySQL, value: []byte{0xde, 0xad}, expect: "X'dead'"},
+		{name: "sqlserver bytes", dialect: DialectSQLServer, value: []byte{0xde, 0xad}, expect: "0xdead"},
+		{name: "valuer", dialect: DialectPostgres, value: sql.NullString{String: "a", Valid: true}, expect: "'a'"},
+		{name: "null valuer", dialect: DialectPostgres, value: sql.NullInt64{}, expect: "NULL"},
+		{name: "decimal", dialect: DialectPostgres, value: decimal.RequireFromString("12345678901234567890.123456789"), expect: "12345678901234567890.123456789"},
+		{name: "null decimal", dialect: DialectPostgres, value: decimal.NullDecimal{}, expect: "NULL"},
+		{name: "named", dialect: DialectPostgres, value: sql.Named("arg1", 5), expect: "5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expect, test.dialect.Literal(test.value))
+		})
+	}
+}
@@ -3,8 +3,30 @@ package ondatra
 import "errors"
 
 var (
-	SqlDBNotSet             = errors.New("cannot run; no sql db set")
-	ErrAlreadyInTransaction = errors.New("already in transaction")
-	NotSetColumns           = errors.New("columns must have at least one set of values")
-	NotSetValues            = errors.New("values must have at least one set of values")
+	SqlDBNotSet               = errors.New("cannot run; no sql db set")
+	ErrAlreadyInTransaction   = errors.New("already in transaction")
+	NotSetColumns             = errors.New("columns must have at least one set of values")
+	NotSetValues              = errors.New("values must have at least one set of values")
+	ErrNamedArgumentNotFound  = errors.New("named argument not found")
+	ErrCaseWithoutWhen        = errors.New("case must have at least one when")
+	ErrRowsNotSlice           = errors.New("rows must be a slice of structs")
+	ErrKeyColumnsNotSet       = errors.New("key columns must be set or tagged as pk")
+	ErrReturningNotSet        = errors.New("returning columns must be set")
+	ErrLockOutsideTransaction = errors.New("row lock must be used in transaction")
+	ErrBatchAborted           = errors.New("statement of batch is not executed")
+	ErrCopyValues             = errors.New("copy row values do not match columns")
+	ErrSchemaMismatch         = errors.New("schema mismatch")
+	ErrDialectNotSupported    = errors.New("dialect is not supported")
+	ErrShardKeyNotSet         = errors.New("shard key is not set")
+	ErrShardNotFound          = errors.New("shard not found")
+	ErrShardOrderNotSet       = errors.New("limit and offset of all shards require order of merged rows")
+	ErrReplicaLag             = errors.New("replication lag exceeds limit")
+	ErrTemplateNotStatic      = errors.New("builder with tenant guard or policies can not be compiled")
+	ErrTemplateArgs           = errors.New("wrong number of template arguments")
+	ErrInvalidIdentifier      = errors.New("invalid identifier")
+	ErrInvalidFilter          = errors.New("invalid filter")
+	ErrPolicyDenied           = errors.New("denied by policy")
+	ErrTenantNotSet           = errors.New("tenant is not set")
+	ErrScopeNotFound          = errors.New("scope not found")
+	ErrJoinWithoutFrom        = errors.New("joins of update or delete require UpdateFrom or DeleteUsing tables")
 )
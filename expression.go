@@ -1,6 +1,7 @@
 package ondatra
 
 import (
+	"fmt"
 	"io"
 	"strings"
 )
@@ -23,49 +24,98 @@ func (e expr) ToSQL() (string, []any, error) {
 		return e.rawSQL, nil, nil
 	}
 
+	placeholders := scanPlaceholders(e.rawSQL)
+	if lookup, ok := namedArguments(placeholders, e.args); ok {
+		return e.bindNamed(placeholders, lookup)
+	}
+
+	var err error
 	var args []any
-	for i, arg := range e.args {
-		switch a := arg.(type) {
-		case Builder:
-			a = a.PlaceholderFormat(nil)
-			newSQL, newArgs, err := a.ToSQL()
-			if err != nil {
-				return "", nil, err
-			}
+	var buffer strings.Builder
+	var last, n int
+	for _, p := range placeholders {
+		if p.kind != placeholderPositional {
+			continue
+		}
+		if n == len(e.args) {
+			break
+		}
 
-			e.rawSQL = replaceNth(e.rawSQL, "?", newSQL, i+1)
-			args = append(args, newArgs...)
-		case Expr:
-			newSQL, newArgs, err := a.ToSQL()
-			if err != nil {
-				return "", nil, err
-			}
+		buffer.WriteString(e.rawSQL[last:p.start])
+		if args, err = writeArgument(e.args[n], &buffer, args); err != nil {
+			return "", nil, err
+		}
+		last = p.end
+		n++
+	}
+	buffer.WriteString(e.rawSQL[last:])
 
-			e.rawSQL = replaceNth(e.rawSQL, "?", newSQL, i+1)
-			args = append(args, newArgs...)
-		default:
-			args = append(args, arg)
+	// arguments without placeholders are passed as is
+	for ; n < len(e.args); n++ {
+		if args, err = writeArgument(e.args[n], io.Discard, args); err != nil {
+			return "", nil, err
 		}
 	}
 
-	return e.rawSQL, args, nil
+	return buffer.String(), args, nil
 }
 
-// Replace the nth occurrence of old in s by new.
-func replaceNth(s, old, new string, n int) string {
-	i := 0
-	for m := 1; m <= n; m++ {
-		x := strings.Index(s[i:], old)
-		if x < 0 {
-			break
+// bindNamed replaces every :name or @name marker with a positional placeholder,
+// the same name may be used several times and its value is repeated in args
+func (e expr) bindNamed(placeholders []placeholder, lookup func(name string) (any, bool)) (string, []any, error) {
+	var err error
+	var args []any
+	var buffer strings.Builder
+	var last int
+	for _, p := range placeholders {
+		if p.kind != placeholderNamed {
+			continue
+		}
+
+		value, ok := lookup(p.name)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s", ErrNamedArgumentNotFound, p.name)
 		}
-		i += x
-		if m == n {
-			return s[:i] + new + s[i+len(old):]
+
+		buffer.WriteString(e.rawSQL[last:p.start])
+		if args, err = writeArgument(value, &buffer, args); err != nil {
+			return "", nil, err
 		}
-		i += len(old)
+		last = p.end
+	}
+	buffer.WriteString(e.rawSQL[last:])
+
+	return buffer.String(), args, nil
+}
+
+// writeArgument writes nested Builder or Expr sql in place of placeholder or the placeholder itself
+func writeArgument(arg any, w io.Writer, args []any) ([]any, error) {
+	switch a := arg.(type) {
+	case Builder:
+		a = a.PlaceholderFormat(nil)
+		newSQL, newArgs, err := a.ToSQL()
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(w, newSQL); err != nil {
+			return nil, err
+		}
+		return append(args, newArgs...), nil
+	case Expr:
+		newSQL, newArgs, err := a.ToSQL()
+		if err != nil {
+			return nil, err
+		}
+		if _, err = io.WriteString(w, newSQL); err != nil {
+			return nil, err
+		}
+		return append(args, newArgs...), nil
+	default:
+		if _, err := io.WriteString(w, "?"); err != nil {
+			return nil, err
+		}
+		return append(args, arg), nil
 	}
-	return s
 }
 
 func writeExpr(expr Expr, w io.Writer, args []any) ([]any, error) {
@@ -102,3 +152,70 @@ func writeExprs(expr []Expr, w io.Writer, sep string, args []any) ([]any, error)
 	}
 	return args, nil
 }
+
+// subqueryBinder is an expression with nested expressions, bindSubqueries returns its copy
+// with every nested Builder replaced by the result of bind
+type subqueryBinder interface {
+	bindSubqueries(bind func(Builder) Builder) Expr
+}
+
+func bindSubqueries(e Expr, bind func(Builder) Builder) Expr {
+	switch v := e.(type) {
+	case Builder:
+		return bind(v)
+	case subqueryBinder:
+		return v.bindSubqueries(bind)
+	default:
+		return e
+	}
+}
+
+func bindSubqueriesExprs(exprs []Expr, bind func(Builder) Builder) []Expr {
+	if len(exprs) == 0 {
+		return exprs
+	}
+	bound := make([]Expr, len(exprs))
+	for i := range exprs {
+		bound[i] = bindSubqueries(exprs[i], bind)
+	}
+	return bound
+}
+
+func bindSubqueriesArgs(args []any, bind func(Builder) Builder) []any {
+	if len(args) == 0 {
+		return args
+	}
+	bound := make([]any, len(args))
+	for i := range args {
+		if e, ok := args[i].(Expr); ok {
+			bound[i] = bindSubqueries(e, bind)
+			continue
+		}
+		bound[i] = args[i]
+	}
+	return bound
+}
+
+func (e expr) bindSubqueries(bind func(Builder) Builder) Expr {
+	e.args = bindSubqueriesArgs(e.args, bind)
+	return e
+}
+
+// whereGroup is where of a statement in parentheses, conditions of tenant and policies are added after it
+// so OR of the where can not bypass them
+type whereGroup []Expr
+
+func (g whereGroup) ToSQL() (string, []any, error) {
+	var buffer strings.Builder
+	buffer.WriteString("(")
+	args, err := writeExprs(g, &buffer, " AND ", nil)
+	if err != nil {
+		return "", nil, err
+	}
+	buffer.WriteString(")")
+	return buffer.String(), args, nil
+}
+
+func (g whereGroup) bindSubqueries(bind func(Builder) Builder) Expr {
+	return whereGroup(bindSubqueriesExprs(g, bind))
+}
@@ -1,6 +1,7 @@
 package ondatra
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,46 @@ func TestExpr_ToSQL(t *testing.T) {
 			),
 			expectQuery: "t > ? AND (a > ?) AND c > ? AND (h > ? AND j > ?)",
 			expectArgs:  []any{1, 2, 3, 4, 5},
+		}, {
+			name: "sub query with several placeholders",
+			expr: NewExpr("? AND ? AND c > ?",
+				NewExpr("a > ? AND b > ?", 1, 2),
+				NewExpr("status = 'new'"),
+				3,
+			),
+			expectQuery: "a > ? AND b > ? AND status = 'new' AND c > ?",
+			expectArgs:  []any{1, 2, 3},
+		}, {
+			name:        "question mark in literals and comments",
+			expr:        NewExpr(`title = 'what?' AND "col?" = ? -- why?`+"\n"+`AND body = $$how?$$ AND x = ?`, 1, 2),
+			expectQuery: `title = 'what?' AND "col?" = ? -- why?` + "\n" + `AND body = $$how?$$ AND x = ?`,
+			expectArgs:  []any{1, 2},
+		}, {
+			name:        "json operators",
+			expr:        NewExpr("tags ?| array['a'] AND tags ?& array['b'] AND tags ?? 'c' AND id = ?", 1),
+			expectQuery: "tags ?| array['a'] AND tags ?& array['b'] AND tags ?? 'c' AND id = ?",
+			expectArgs:  []any{1},
+		}, {
+			name: "named map",
+			expr: NewExpr("user_id = :user_id OR owner_id = :user_id AND created_at::date > @since",
+				map[string]any{"user_id": 1, "since": "2024-01-01"},
+			),
+			expectQuery: "user_id = ? OR owner_id = ? AND created_at::date > ?",
+			expectArgs:  []any{1, 1, "2024-01-01"},
+		}, {
+			name:        "named sql args",
+			expr:        NewExpr("a = :a AND b = ':b' AND c = :c", sql.Named("a", 1), sql.Named("c", 2)),
+			expectQuery: "a = ? AND b = ':b' AND c = ?",
+			expectArgs:  []any{1, 2},
+		}, {
+			name: "named struct",
+			expr: NewExpr("id = :id AND name = :name AND (:sub)", struct {
+				ID   int64  `db:"id,column,pk"`
+				Name string `db:"name,column"`
+				Sub  Expr
+			}{ID: 1, Name: "test", Sub: NewExpr("x > ?", 2)}),
+			expectQuery: "id = ? AND name = ? AND (x > ?)",
+			expectArgs:  []any{int64(1), "test", 2},
 		},
 	}
 
@@ -40,3 +81,8 @@ func TestExpr_ToSQL(t *testing.T) {
 		})
 	}
 }
+
+func TestExpr_ToSQLNamedNotFound(t *testing.T) {
+	_, _, err := NewExpr("a = :a AND b = :b", map[string]any{"a": 1}).ToSQL()
+	assert.ErrorIs(t, err, ErrNamedArgumentNotFound)
+}
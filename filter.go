@@ -0,0 +1,478 @@
+package ondatra
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	OperatorEQ      FilterOperator = "eq"
+	OperatorNEQ     FilterOperator = "neq"
+	OperatorLT      FilterOperator = "lt"
+	OperatorLTE     FilterOperator = "lte"
+	OperatorGT      FilterOperator = "gt"
+	OperatorGTE     FilterOperator = "gte"
+	OperatorLike    FilterOperator = "like"
+	OperatorNotLike FilterOperator = "nlike"
+	OperatorIn      FilterOperator = "in"
+	OperatorNotIn   FilterOperator = "nin"
+	OperatorNull    FilterOperator = "null"
+
+	filterParamSort   = "sort"
+	filterParamLimit  = "limit"
+	filterParamOffset = "offset"
+)
+
+var filterSQLOperators = map[FilterOperator]string{
+	OperatorEQ:      "=",
+	OperatorNEQ:     "!=",
+	OperatorLT:      "<",
+	OperatorLTE:     "<=",
+	OperatorGT:      ">",
+	OperatorGTE:     ">=",
+	OperatorLike:    "LIKE",
+	OperatorNotLike: "NOT LIKE",
+}
+
+// FilterOperator is an operator of filter param, e.g. created_at[gte]=2024-01-01
+type FilterOperator string
+
+// FilterError describes rejected filter param
+type FilterError struct {
+	Field    string
+	Operator FilterOperator
+	Value    string
+	Reason   string
+}
+
+func (e FilterError) Error() string {
+	var buffer strings.Builder
+	buffer.WriteString(ErrInvalidFilter.Error())
+	buffer.WriteString(": field ")
+	buffer.WriteString(strconv.Quote(e.Field))
+	if e.Operator != "" {
+		buffer.WriteString(" operator ")
+		buffer.WriteString(strconv.Quote(string(e.Operator)))
+	}
+	if e.Value != "" {
+		buffer.WriteString(" value ")
+		buffer.WriteString(strconv.Quote(e.Value))
+	}
+	buffer.WriteString(": ")
+	buffer.WriteString(e.Reason)
+	return buffer.String()
+}
+
+func (e FilterError) Unwrap() error {
+	return ErrInvalidFilter
+}
+
+// FilterField is a field allowed in Filter, use FilterColumn to create it from Column[T]
+type FilterField interface {
+	Name() string
+	Column() string
+	Condition(operator FilterOperator, values []string) (Expr, error)
+}
+
+type filterColumn[T comparable] struct {
+	column    Column[T]
+	operators []FilterOperator
+}
+
+// FilterColumn allows filtering by column with operators, values are converted to T.
+// Without operators only OperatorEQ is allowed.
+func FilterColumn[T comparable](column Column[T], operators ...FilterOperator) FilterField {
+	if len(operators) == 0 {
+		operators = []FilterOperator{OperatorEQ}
+	}
+	return filterColumn[T]{
+		column:    column,
+		operators: operators,
+	}
+}
+
+func (c filterColumn[T]) Name() string {
+	return c.column.Name
+}
+
+func (c filterColumn[T]) Column() string {
+	return c.column.QualifiedName
+}
+
+func (c filterColumn[T]) Condition(operator FilterOperator, values []string) (Expr, error) {
+	if !slices.Contains(c.operators, operator) {
+		return nil, FilterError{Field: c.Name(), Operator: operator, Reason: "operator is not allowed"}
+	}
+	if len(values) == 0 {
+		return nil, FilterError{Field: c.Name(), Operator: operator, Reason: "value is empty"}
+	}
+
+	if operator == OperatorNull {
+		isNull, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return nil, FilterError{Field: c.Name(), Operator: operator, Value: values[0], Reason: "value must be boolean"}
+		}
+		if isNull {
+			return c.column.IsNull(), nil
+		}
+		return c.column.IsNotNull(), nil
+	}
+
+	parsed := make([]T, len(values))
+	for i, value := range values {
+		v, err := ParseFilterValue[T](value)
+		if err != nil {
+			return nil, FilterError{Field: c.Name(), Operator: operator, Value: value, Reason: err.Error()}
+		}
+		parsed[i] = v
+	}
+
+	switch operator {
+	case OperatorIn:
+		return c.column.IN(parsed...), nil
+	case OperatorNotIn:
+		return c.column.NIN(parsed...), nil
+	}
+
+	if len(parsed) > 1 {
+		return nil, FilterError{Field: c.Name(), Operator: operator, Reason: "operator accepts one value"}
+	}
+
+	sqlOperator, ok := filterSQLOperators[operator]
+	if !ok {
+		return nil, FilterError{Field: c.Name(), Operator: operator, Reason: "unknown operator"}
+	}
+	return Value[T](fmt.Sprintf("%s %s ?", c.column.QualifiedName, sqlOperator)).Value(parsed[0]), nil
+}
+
+// ParseFilterValue converts filter value to T, supported strings, numbers, booleans, time.Time in RFC3339 or date,
+// decimal.Decimal and types implementing encoding.TextUnmarshaler
+func ParseFilterValue[T any](value string) (T, error) {
+	var result T
+
+	switch p := any(&result).(type) {
+	case *string:
+		*p = value
+		return result, nil
+	case *time.Time:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			if t, err = time.Parse(time.DateOnly, value); err != nil {
+				return result, fmt.Errorf("value must be time in RFC3339 or date format")
+			}
+		}
+		*p = t
+		return result, nil
+	case *decimal.Decimal:
+		d, err := decimal.NewFromString(value)
+		if err != nil {
+			return result, fmt.Errorf("value must be decimal")
+		}
+		*p = d
+		return result, nil
+	case encoding.TextUnmarshaler:
+		if err := p.UnmarshalText([]byte(value)); err != nil {
+			return result, fmt.Errorf("value is invalid: %w", err)
+		}
+		return result, nil
+	}
+
+	v := reflect.ValueOf(&result).Elem()
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return result, fmt.Errorf("value must be boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return result, fmt.Errorf("value must be integer")
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return result, fmt.Errorf("value must be unsigned integer")
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return result, fmt.Errorf("value must be number")
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		v.SetString(value)
+	default:
+		return result, fmt.Errorf("type %s is not supported", v.Type())
+	}
+	return result, nil
+}
+
+// Filter is a whitelist of fields, operators and sort fields to build where, order by, limit and offset
+// from query params, e.g. ?status=active&created_at[gte]=2024-01-01&sort=-created_at&limit=20.
+// Unknown fields and operators are rejected, field names never get into SQL.
+type Filter struct {
+	fields       []FilterField
+	sortFields   []string
+	defaultSort  []string
+	defaultLimit int64
+	maxLimit     int64
+	ignored      []string
+}
+
+func NewFilter(fields ...FilterField) Filter {
+	return Filter{
+		fields: fields,
+	}
+}
+
+// Sortable allows sorting by fields, sort=name is ascending and sort=-name is descending
+func (f Filter) Sortable(names ...string) Filter {
+	f.sortFields = append(slices.Clone(f.sortFields), names...)
+	return f
+}
+
+// DefaultSort is used when sort param is not set, it has the same format as sort param
+func (f Filter) DefaultSort(sort ...string) Filter {
+	f.defaultSort = sort
+	return f
+}
+
+// Limit set default limit and max limit, zero max limit allows any limit
+func (f Filter) Limit(defaultLimit, maxLimit int64) Filter {
+	f.defaultLimit = defaultLimit
+	f.maxLimit = maxLimit
+	return f
+}
+
+// IgnoreParams skips query params read by other parts of API, e.g. scope param of ScopeRegistry.FromQuery,
+// other unknown params are still rejected by Parse
+func (f Filter) IgnoreParams(params ...string) Filter {
+	f.ignored = append(slices.Clone(f.ignored), params...)
+	return f
+}
+
+// Parse returns clause of url query params, field[operator]=value is a condition,
+// values of in and nin operators are separated by comma
+func (f Filter) Parse(values url.Values) (Clause, error) {
+	var conditions []Expr
+	var sort []string
+	var limit, offset string
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if slices.Contains(f.ignored, key) {
+			continue
+		}
+		switch key {
+		case filterParamSort:
+			for _, value := range values[key] {
+				sort = append(sort, strings.Split(value, ",")...)
+			}
+			continue
+		case filterParamLimit:
+			limit = values.Get(key)
+			continue
+		case filterParamOffset:
+			offset = values.Get(key)
+			continue
+		}
+
+		name, operator, err := parseFilterKey(key)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values[key] {
+			fieldValues := []string{value}
+			if operator == OperatorIn || operator == OperatorNotIn {
+				fieldValues = strings.Split(value, ",")
+			}
+			condition, err := f.condition(name, operator, fieldValues)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+
+	return f.clause(conditions, sort, limit, offset)
+}
+
+// ParseJSON returns clause of JSON filter, e.g.
+// {"filter": {"status": "active", "created_at": {"gte": "2024-01-01"}, "id": {"in": [1, 2]}}, "sort": ["-created_at"], "limit": 20}
+func (f Filter) ParseJSON(data []byte) (Clause, error) {
+	var request struct {
+		Filter map[string]json.RawMessage `json:"filter"`
+		Sort   []string                   `json:"sort"`
+		Limit  json.Number                `json:"limit"`
+		Offset json.Number                `json:"offset"`
+	}
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+
+	names := make([]string, 0, len(request.Filter))
+	for name := range request.Filter {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var conditions []Expr
+	for _, name := range names {
+		raw := request.Filter[name]
+
+		var operators map[FilterOperator]json.RawMessage
+		if err := json.Unmarshal(raw, &operators); err != nil {
+			operators = map[FilterOperator]json.RawMessage{OperatorEQ: raw}
+		}
+
+		operatorNames := make([]FilterOperator, 0, len(operators))
+		for operator := range operators {
+			operatorNames = append(operatorNames, operator)
+		}
+		slices.Sort(operatorNames)
+
+		for _, operator := range operatorNames {
+			values, err := jsonFilterValues(operators[operator])
+			if err != nil {
+				return nil, FilterError{Field: name, Operator: operator, Value: string(operators[operator]), Reason: err.Error()}
+			}
+			condition, err := f.condition(name, operator, values)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+
+	return f.clause(conditions, request.Sort, request.Limit.String(), request.Offset.String())
+}
+
+func (f Filter) field(name string) (FilterField, bool) {
+	for _, field := range f.fields {
+		if field.Name() == name {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+func (f Filter) condition(name string, operator FilterOperator, values []string) (Expr, error) {
+	field, ok := f.field(name)
+	if !ok {
+		return nil, FilterError{Field: name, Reason: "unknown field"}
+	}
+	return field.Condition(operator, values)
+}
+
+func (f Filter) clause(conditions []Expr, sort []string, limit, offset string) (Clause, error) {
+	clauses := Clauses{WhereExpr(conditions...)}
+
+	if len(sort) == 0 {
+		sort = f.defaultSort
+	}
+	for _, s := range sort {
+		name, desc := strings.CutPrefix(strings.TrimSpace(s), "-")
+		field, ok := f.field(name)
+		if !ok || !slices.Contains(f.sortFields, name) {
+			return nil, FilterError{Field: name, Reason: "sort is not allowed"}
+		}
+		if desc {
+			clauses = append(clauses, OrderBy(field.Column()+" DESC"))
+		} else {
+			clauses = append(clauses, OrderBy(field.Column()+" ASC"))
+		}
+	}
+
+	limitValue, err := parseFilterNumber(filterParamLimit, limit, f.defaultLimit)
+	if err != nil {
+		return nil, err
+	}
+	if f.maxLimit > 0 {
+		if limitValue > f.maxLimit {
+			return nil, FilterError{Field: filterParamLimit, Value: limit, Reason: fmt.Sprintf("limit must not exceed %d", f.maxLimit)}
+		}
+		if limitValue == 0 {
+			limitValue = f.maxLimit
+		}
+	}
+	offsetValue, err := parseFilterNumber(filterParamOffset, offset, 0)
+	if err != nil {
+		return nil, err
+	}
+	if limitValue > 0 || offsetValue > 0 {
+		clauses = append(clauses, LimitOffset(limitValue, offsetValue))
+	}
+
+	return clauses, nil
+}
+
+// parseFilterKey splits key field[operator], key without operator is OperatorEQ
+func parseFilterKey(key string) (string, FilterOperator, error) {
+	name, operator, ok := strings.Cut(key, "[")
+	if !ok {
+		return key, OperatorEQ, nil
+	}
+	operator, ok = strings.CutSuffix(operator, "]")
+	if !ok || operator == "" {
+		return "", "", FilterError{Field: key, Reason: "param must be field or field[operator]"}
+	}
+	return name, FilterOperator(operator), nil
+}
+
+func parseFilterNumber(name, value string, defaultValue int64) (int64, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || number < 0 {
+		return 0, FilterError{Field: name, Value: value, Reason: "value must be not negative integer"}
+	}
+	return number, nil
+}
+
+// jsonFilterValues converts JSON value or array of values to strings parsed by FilterField
+func jsonFilterValues(raw json.RawMessage) ([]string, error) {
+	var array []json.RawMessage
+	if err := json.Unmarshal(raw, &array); err != nil {
+		array = []json.RawMessage{raw}
+	}
+
+	values := make([]string, len(array))
+	for i, item := range array {
+		var value any
+		decoder := json.NewDecoder(strings.NewReader(string(item)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case string:
+			values[i] = v
+		case json.Number:
+			values[i] = v.String()
+		case bool:
+			values[i] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("value must be string, number or boolean")
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,118 @@
+package ondatra
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	filter := NewFilter(
+		FilterColumn(NewColumn[string]("orders", "status"), OperatorEQ, OperatorIn),
+		FilterColumn(NewColumn[int64]("orders", "amount"), OperatorGT, OperatorLTE),
+		FilterColumn(NewColumn[time.Time]("orders", "created_at"), OperatorGTE, OperatorLT),
+		FilterColumn(NewColumn[string]("orders", "deleted_at"), OperatorNull),
+	).Sortable("created_at", "amount").DefaultSort("-created_at").Limit(20, 100)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		name        string
+		query       string
+		json        string
+		expectQuery string
+		expectArgs  []any
+		expectErr   string
+	}{
+		{
+			name:        "defaults",
+			query:       "",
+			json:        `{}`,
+			expectQuery: "SELECT id FROM orders ORDER BY \"orders\".created_at DESC LIMIT 20",
+		}, {
+			name:  "conditions",
+			query: "status=active&amount[gt]=10&created_at[gte]=2024-01-01&deleted_at[null]=true&sort=amount,-created_at&limit=5&offset=10",
+			json: `{"filter": {"status": "active", "amount": {"gt": 10}, "created_at": {"gte": "2024-01-01"}, ` +
+				`"deleted_at": {"null": true}}, "sort": ["amount", "-created_at"], "limit": 5, "offset": 10}`,
+			expectQuery: "SELECT id FROM orders WHERE \"orders\".amount > ? AND \"orders\".created_at >= ? " +
+				"AND \"orders\".deleted_at IS NULL AND \"orders\".status = ? " +
+				"ORDER BY \"orders\".amount ASC, \"orders\".created_at DESC LIMIT 5 OFFSET 10",
+			expectArgs: []any{int64(10), createdAt, "active"},
+		}, {
+			name:        "in",
+			query:       "status[in]=new,paid",
+			json:        `{"filter": {"status": {"in": ["new", "paid"]}}}`,
+			expectQuery: "SELECT id FROM orders WHERE \"orders\".status IN (?,?) ORDER BY \"orders\".created_at DESC LIMIT 20",
+			expectArgs:  []any{"new", "paid"},
+		}, {
+			name:      "unknown field",
+			query:     "password=secret",
+			json:      `{"filter": {"password": "secret"}}`,
+			expectErr: `invalid filter: field "password": unknown field`,
+		}, {
+			name:      "not allowed operator",
+			query:     "status[like]=a%25",
+			json:      `{"filter": {"status": {"like": "a%"}}}`,
+			expectErr: `invalid filter: field "status" operator "like": operator is not allowed`,
+		}, {
+			name:      "invalid value",
+			query:     "amount[gt]=ten",
+			json:      `{"filter": {"amount": {"gt": "ten"}}}`,
+			expectErr: `invalid filter: field "amount" operator "gt" value "ten": value must be integer`,
+		}, {
+			name:      "not sortable",
+			query:     "sort=status",
+			json:      `{"sort": ["status"]}`,
+			expectErr: `invalid filter: field "status": sort is not allowed`,
+		}, {
+			name:      "limit exceeded",
+			query:     "limit=1000",
+			json:      `{"limit": 1000}`,
+			expectErr: `invalid filter: field "limit" value "1000": limit must not exceed 100`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := url.ParseQuery(test.query)
+			assert.NoError(t, err)
+
+			queryClause, queryErr := filter.Parse(values)
+			jsonClause, jsonErr := filter.ParseJSON([]byte(test.json))
+
+			if test.expectErr != "" {
+				assert.ErrorIs(t, queryErr, ErrInvalidFilter)
+				assert.EqualError(t, queryErr, test.expectErr)
+				assert.ErrorIs(t, jsonErr, ErrInvalidFilter)
+				assert.EqualError(t, jsonErr, test.expectErr)
+				return
+			}
+			assert.NoError(t, queryErr)
+			assert.NoError(t, jsonErr)
+
+			for _, clause := range []Clause{queryClause, jsonClause} {
+				query, args, err := NewEmptyBuilder().Select("id").From("orders").Clauses(clause).ToSQL()
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectQuery, query)
+				assert.Equal(t, test.expectArgs, args)
+			}
+		})
+	}
+}
+
+func TestFilterIgnoreParams(t *testing.T) {
+	filter := NewFilter(FilterColumn(NewColumn[string]("orders", "status")))
+	values := url.Values{"status": {"paid"}, "scope": {"recent"}}
+
+	_, err := filter.Parse(values)
+	assert.EqualError(t, err, `invalid filter: field "scope": unknown field`)
+
+	clause, err := filter.IgnoreParams("scope").Parse(values)
+	assert.NoError(t, err)
+	query, args, err := NewEmptyBuilder().Select("id").From("orders").Clauses(clause).ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM orders WHERE \"orders\".status = ?", query)
+	assert.Equal(t, []any{"paid"}, args)
+}
@@ -3,7 +3,9 @@ module github.com/stepanbukhtii/ondatra
 go 1.21
 
 require (
+	github.com/DATA-DOG/go-sqlmock v1.5.2
 	github.com/jmoiron/sqlx v1.3.5
+	github.com/mattn/go-sqlite3 v1.14.22
 	github.com/shopspring/decimal v1.3.1
 	github.com/stretchr/testify v1.8.4
 )
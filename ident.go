@@ -0,0 +1,345 @@
+package ondatra
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	identTable identKind = iota
+	identColumn
+	identOrder
+)
+
+// reservedWords are quoted in strict mode, it is a common subset of postgres, mysql, sqlite and sqlserver keywords
+var reservedWords = map[string]bool{
+	"all": true, "alter": true, "and": true, "any": true, "as": true, "asc": true, "between": true, "both": true,
+	"by": true, "case": true, "cast": true, "check": true, "column": true, "constraint": true, "create": true,
+	"cross": true, "current_date": true, "current_time": true, "current_timestamp": true, "current_user": true,
+	"default": true, "delete": true, "desc": true, "distinct": true, "do": true, "drop": true, "else": true,
+	"end": true, "except": true, "exists": true, "false": true, "fetch": true, "for": true, "foreign": true,
+	"from": true, "full": true, "grant": true, "group": true, "having": true, "in": true, "index": true,
+	"inner": true, "insert": true, "intersect": true, "into": true, "is": true, "join": true, "key": true,
+	"left": true, "like": true, "limit": true, "natural": true, "not": true, "null": true, "offset": true,
+	"on": true, "or": true, "order": true, "outer": true, "primary": true, "range": true, "references": true,
+	"right": true, "row": true, "rows": true, "select": true, "session_user": true, "set": true, "table": true,
+	"then": true, "to": true, "true": true, "union": true, "unique": true, "update": true, "user": true,
+	"using": true, "values": true, "when": true, "where": true, "window": true, "with": true,
+}
+
+// Ident is an identifier of table or column, parts of qualified identifier are separated by dot, e.g. public.users.
+// As Expr it is quoted by double quotes, as table, select or group by expression of builder it is quoted
+// by the builder dialect, e.g. SelectExpr(Ident("u.name")) of mysql is `u`.`name`.
+type Ident string
+
+func (i Ident) ToSQL() (string, []any, error) {
+	if err := validateIdent(string(i)); err != nil {
+		return "", nil, err
+	}
+	return Dialect("").QuoteIdent(string(i)), nil, nil
+}
+
+// QuoteIdent quotes every part of qualified identifier, * is not quoted
+func (d Dialect) QuoteIdent(ident string) string {
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		if part != "*" {
+			parts[i] = d.quoteIdentPart(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func (d Dialect) quoteIdentPart(part string) string {
+	switch d {
+	case DialectMySQL:
+		return "`" + strings.ReplaceAll(part, "`", "``") + "`"
+	case DialectSQLServer:
+		return "[" + strings.ReplaceAll(part, "]", "]]") + "]"
+	default:
+		return `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+	}
+}
+
+// quoteReserved quotes only parts of identifier which are reserved words
+func (d Dialect) quoteReserved(ident string) string {
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		if reservedWords[strings.ToLower(part)] {
+			parts[i] = d.quoteIdentPart(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// IdentError is returned in strict mode for an identifier which is not safe or not known
+type IdentError struct {
+	Ident  string
+	Reason string
+}
+
+func (e IdentError) Error() string {
+	return fmt.Sprintf("%s %q: %s", ErrInvalidIdentifier, e.Ident, e.Reason)
+}
+
+func (e IdentError) Unwrap() error {
+	return ErrInvalidIdentifier
+}
+
+// Strict enables validation of identifiers passed as strings to Table, From, Into, UpdateFrom, Columns,
+// GroupBy, OrderBy, Set and SetMap. Identifiers must be letters, digits and underscores separated by dots,
+// reserved words are quoted. With tables identifiers must be also known by table metadata,
+// qualifier of a column must be one of the tables or their aliases.
+func (b Builder) Strict(tables ...Table) Builder {
+	b.strict = true
+	b.strictTables = tables
+	return b
+}
+
+type identKind int
+
+// identExpr is an identifier passed as string, it is rendered as is unless the builder is strict
+type identExpr struct {
+	kind  identKind
+	ident string
+	sql   string
+}
+
+func (e identExpr) ToSQL() (string, []any, error) {
+	if e.sql != "" {
+		return e.sql, nil, nil
+	}
+	return e.ident, nil, nil
+}
+
+// assignExpr is column = value of update
+type assignExpr struct {
+	column identExpr
+	value  any
+}
+
+func newAssignExpr(column string, value any) assignExpr {
+	return assignExpr{
+		column: identExpr{kind: identColumn, ident: column},
+		value:  value,
+	}
+}
+
+func (e assignExpr) bindSubqueries(bind func(Builder) Builder) Expr {
+	e.value = bindSubqueriesArgs([]any{e.value}, bind)[0]
+	return e
+}
+
+func (e assignExpr) ToSQL() (string, []any, error) {
+	column, _, err := e.column.ToSQL()
+	if err != nil {
+		return "", nil, err
+	}
+	return NewExpr(column+" = ?", e.value).ToSQL()
+}
+
+// applyIdents quotes Ident by the builder dialect, in strict mode it also validates and quotes identifiers
+func (b Builder) applyIdents() (Builder, error) {
+	quote := b.dialectIdent
+	if b.strict {
+		quote = b.strictExpr
+	}
+
+	var err error
+	if b.table, err = quote(b.table); err != nil {
+		return b, err
+	}
+	for _, exprs := range []*[]Expr{&b.from, &b.groupBys, &b.orderByParts, &b.updateValues, &b.selectExpr} {
+		bound := make([]Expr, len(*exprs))
+		for i, e := range *exprs {
+			if bound[i], err = quote(e); err != nil {
+				return b, err
+			}
+		}
+		*exprs = bound
+	}
+	if !b.strict {
+		return b, nil
+	}
+
+	columns := make([]string, len(b.columns))
+	for i, column := range b.columns {
+		if columns[i], err = b.strictIdent(identExpr{kind: identColumn, ident: column}); err != nil {
+			return b, err
+		}
+	}
+	b.columns = columns
+
+	return b, nil
+}
+
+// dialectIdent quotes Ident by the builder dialect, other expressions are kept
+func (b Builder) dialectIdent(e Expr) (Expr, error) {
+	v, ok := e.(Ident)
+	if !ok {
+		return e, nil
+	}
+	if err := validateIdent(string(v)); err != nil {
+		return nil, err
+	}
+	return NewExpr(b.dialect.QuoteIdent(string(v))), nil
+}
+
+func (b Builder) strictExpr(e Expr) (Expr, error) {
+	var err error
+	switch v := e.(type) {
+	case identExpr:
+		v.sql, err = b.strictIdent(v)
+		return v, err
+	case assignExpr:
+		v.column.sql, err = b.strictIdent(v.column)
+		return v, err
+	case Ident:
+		return b.dialectIdent(v)
+	default:
+		return e, nil
+	}
+}
+
+// strictIdent returns identifier with quoted reserved words, table may have alias and order may have direction
+func (b Builder) strictIdent(e identExpr) (string, error) {
+	fields := strings.Fields(e.ident)
+	if len(fields) == 0 {
+		return "", IdentError{Ident: e.ident, Reason: "identifier is empty"}
+	}
+
+	name := fields[0]
+	if err := validateIdent(name); err != nil {
+		return "", err
+	}
+	if err := b.knownIdent(e.kind, name); err != nil {
+		return "", err
+	}
+
+	rest := fields[1:]
+	switch e.kind {
+	case identTable:
+		alias := rest
+		if len(alias) == 2 && strings.EqualFold(alias[0], "AS") {
+			alias = alias[1:]
+		}
+		if len(alias) > 1 {
+			return "", IdentError{Ident: e.ident, Reason: "table must be name or name with alias"}
+		}
+		if len(alias) == 1 {
+			if err := validateIdent(alias[0]); err != nil || strings.Contains(alias[0], ".") {
+				return "", IdentError{Ident: e.ident, Reason: "alias is not valid"}
+			}
+			alias[0] = b.dialect.quoteReserved(alias[0])
+		}
+	case identOrder:
+		if !validOrderDirection(rest) {
+			return "", IdentError{Ident: e.ident, Reason: "order must be column with ASC, DESC and NULLS FIRST or NULLS LAST"}
+		}
+	default:
+		if len(rest) > 0 {
+			return "", IdentError{Ident: e.ident, Reason: "column must be name"}
+		}
+	}
+
+	return strings.Join(append([]string{b.dialect.quoteReserved(name)}, rest...), " "), nil
+}
+
+// knownIdent checks identifier by table metadata of strict mode
+func (b Builder) knownIdent(kind identKind, name string) error {
+	if len(b.strictTables) == 0 {
+		return nil
+	}
+
+	parts := strings.Split(name, ".")
+	last := parts[len(parts)-1]
+	if kind != identTable && len(parts) > 1 {
+		table, ok := b.strictQualifiers()[parts[len(parts)-2]]
+		if !ok {
+			return IdentError{Ident: name, Reason: "unknown table"}
+		}
+		if last != "*" && !slices.Contains(table.columns, last) {
+			return IdentError{Ident: name, Reason: "unknown column"}
+		}
+		return nil
+	}
+
+	for _, table := range b.strictTables {
+		if kind == identTable && table.name == name {
+			return nil
+		}
+		if kind != identTable && (last == "*" || slices.Contains(table.columns, last)) {
+			return nil
+		}
+	}
+
+	if kind == identTable {
+		return IdentError{Ident: name, Reason: "unknown table"}
+	}
+	return IdentError{Ident: name, Reason: "unknown column"}
+}
+
+// strictQualifiers returns tables of strict mode by names and by aliases of the statement tables and joins
+func (b Builder) strictQualifiers() map[string]Table {
+	qualifiers := make(map[string]Table)
+	for _, table := range b.strictTables {
+		qualifiers[unqualifiedTable(table.name)] = table
+	}
+
+	for _, table := range append([]Expr{b.table}, b.from...) {
+		if table == nil {
+			continue
+		}
+		refs, _ := parseTableRefs(tableSQL(table))
+		for _, ref := range refs {
+			if t, ok := qualifiers[unqualifiedTable(ref.name)]; ok && ref.name != "" {
+				qualifiers[ref.alias] = t
+			}
+		}
+	}
+	for _, join := range b.joins {
+		if aliasJoin, _, ok := asAliasJoin(join); ok {
+			qualifiers[aliasJoin.alias] = aliasJoin.table
+		}
+	}
+	return qualifiers
+}
+
+// validateIdent checks that identifier is letters, digits and underscores separated by dots, the last part may be *
+func validateIdent(ident string) error {
+	parts := strings.Split(ident, ".")
+	if len(parts) > 3 {
+		return IdentError{Ident: ident, Reason: "identifier has too many parts"}
+	}
+	for i, part := range parts {
+		if part == "*" && i == len(parts)-1 && i > 0 {
+			continue
+		}
+		if part == "" || !isStrictIdentStart(part[0]) {
+			return IdentError{Ident: ident, Reason: "identifier must start with letter or underscore"}
+		}
+		for j := 1; j < len(part); j++ {
+			if !isStrictIdentStart(part[j]) && !isDigit(part[j]) {
+				return IdentError{Ident: ident, Reason: "identifier must contain only letters, digits and underscores"}
+			}
+		}
+	}
+	return nil
+}
+
+// isStrictIdentStart reports whether c is ASCII letter or underscore
+func isStrictIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func validOrderDirection(words []string) bool {
+	if len(words) > 0 && (strings.EqualFold(words[0], "ASC") || strings.EqualFold(words[0], "DESC")) {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return true
+	}
+	return len(words) == 2 && strings.EqualFold(words[0], "NULLS") &&
+		(strings.EqualFold(words[1], "FIRST") || strings.EqualFold(words[1], "LAST"))
+}
@@ -0,0 +1,126 @@
+package ondatra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteIdent(t *testing.T) {
+	var tests = []struct {
+		dialect Dialect
+		ident   string
+		expect  string
+	}{
+		{dialect: DialectPostgres, ident: "public.users", expect: `"public"."users"`},
+		{dialect: DialectPostgres, ident: `we"ird`, expect: `"we""ird"`},
+		{dialect: DialectMySQL, ident: "users.*", expect: "`users`.*"},
+		{dialect: DialectSQLServer, ident: "dbo.order", expect: "[dbo].[order]"},
+		{dialect: DialectSQLite, ident: "user", expect: `"user"`},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.dialect)+" "+test.ident, func(t *testing.T) {
+			assert.Equal(t, test.expect, test.dialect.QuoteIdent(test.ident))
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	users := NewTable("users", []string{"id", "name", "order", "group_id"})
+
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+		expectErr   string
+	}{
+		{
+			name:        "permissive",
+			builder:     NewEmptyBuilder().Select("id").From("users u; DROP TABLE users").OrderBy("id; --"),
+			expectQuery: "SELECT id FROM users u; DROP TABLE users ORDER BY id; --",
+		}, {
+			name: "reserved words",
+			builder: NewEmptyBuilder().Strict().Select("id").From("user AS u").
+				GroupBy("u.group").OrderBy("u.order DESC NULLS LAST", "id"),
+			expectQuery: `SELECT id FROM "user" AS u GROUP BY u."group" ORDER BY u."order" DESC NULLS LAST, id`,
+		}, {
+			name:        "mysql",
+			builder:     NewEmptyBuilder().Dialect(DialectMySQL).Strict(users).Update().Table("users").Set("order", 1).Where("id = ?", 2),
+			expectQuery: "UPDATE users SET `order` = ? WHERE id = ?",
+			expectArgs:  []any{1, 2},
+		}, {
+			name:        "insert columns",
+			builder:     NewEmptyBuilder().Strict(users).Insert().Into("users").Columns("name", "order").Values("a", 1),
+			expectQuery: `INSERT INTO users (name, "order") VALUES (?,?)`,
+			expectArgs:  []any{"a", 1},
+		}, {
+			name:        "ident",
+			builder:     NewEmptyBuilder().Dialect(DialectSQLServer).Strict().Select().SelectExpr(Ident("u.name")).From("users u"),
+			expectQuery: "SELECT [u].[name] FROM users u",
+		}, {
+			name:        "ident without strict",
+			builder:     NewEmptyBuilder().Dialect(DialectMySQL).Select().SelectExpr(Ident("u.name")).From("users u").GroupByExpr(Ident("u.group")),
+			expectQuery: "SELECT `u`.`name` FROM users u GROUP BY `u`.`group`",
+		}, {
+			name:        "ident as expr",
+			builder:     NewEmptyBuilder().Dialect(DialectMySQL).Select("id").From("users").WhereExpr(NewExpr("? IS NULL", Ident("name"))),
+			expectQuery: "SELECT id FROM users WHERE \"name\" IS NULL",
+		}, {
+			name:      "injection in table",
+			builder:   NewEmptyBuilder().Strict().Select("id").From("users u; DROP TABLE users"),
+			expectErr: `invalid identifier "users u; DROP TABLE users": table must be name or name with alias`,
+		}, {
+			name:      "injection in order",
+			builder:   NewEmptyBuilder().Strict().Select("id").From("users").OrderBy("(SELECT 1)"),
+			expectErr: `invalid identifier "(SELECT": identifier must start with letter or underscore`,
+		}, {
+			name:      "injection in set",
+			builder:   NewEmptyBuilder().Strict().Update().Table("users").Set("name = 'x', admin", true),
+			expectErr: `invalid identifier "name = 'x', admin": column must be name`,
+		}, {
+			name:      "unknown table",
+			builder:   NewEmptyBuilder().Strict(users).Select("id").From("accounts"),
+			expectErr: `invalid identifier "accounts": unknown table`,
+		}, {
+			name:      "unknown column",
+			builder:   NewEmptyBuilder().Strict(users).Select("id").From("users").OrderBy("password"),
+			expectErr: `invalid identifier "password": unknown column`,
+		}, {
+			name:        "qualified columns",
+			builder:     NewEmptyBuilder().Strict(users).Select("u.id").From("users u").OrderBy("users.name", "u.order"),
+			expectQuery: `SELECT u.id FROM users u ORDER BY users.name, u."order"`,
+		}, {
+			name:      "unknown qualifier",
+			builder:   NewEmptyBuilder().Strict(users).Select("id").From("users").OrderBy("orders.name"),
+			expectErr: `invalid identifier "orders.name": unknown table`,
+		}, {
+			name:      "unknown qualified column",
+			builder:   NewEmptyBuilder().Strict(users).Select("id").From("users AS u").GroupBy("u.password"),
+			expectErr: `invalid identifier "u.password": unknown column`,
+		}, {
+			name:      "dollar",
+			builder:   NewEmptyBuilder().Strict().Select("id").From("users").OrderBy("a$b"),
+			expectErr: `invalid identifier "a$b": identifier must contain only letters, digits and underscores`,
+		}, {
+			name:      "not ascii",
+			builder:   NewEmptyBuilder().Strict().Select("id").From("usérs"),
+			expectErr: `invalid identifier "usérs": identifier must contain only letters, digits and underscores`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			if test.expectErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidIdentifier)
+				assert.EqualError(t, err, test.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
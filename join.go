@@ -1,6 +1,9 @@
 package ondatra
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type JoinExpr interface {
 	Expr
@@ -88,3 +91,28 @@ func (b aliasJoinBuilder) RelatedTable(relatedTable string) JoinExpr {
 	b.relatedTable = relatedTable
 	return b
 }
+
+// guardedJoin is a join built by NewJoin with conditions of tenant and policies added to its ON
+type guardedJoin struct {
+	join       aliasJoinBuilder
+	conditions []Expr
+}
+
+func (j guardedJoin) ToSQL() (string, []any, error) {
+	args := append([]any{j.join}, toArgs(j.conditions)...)
+	return NewExpr("?"+strings.Repeat(" AND ?", len(j.conditions)), args...).ToSQL()
+}
+
+// asAliasJoin returns join built by NewJoin and conditions already added to it
+func asAliasJoin(join Expr) (aliasJoinBuilder, []Expr, bool) {
+	switch j := join.(type) {
+	case *aliasJoinBuilder:
+		return *j, nil, true
+	case aliasJoinBuilder:
+		return j, nil, true
+	case guardedJoin:
+		return j.join, j.conditions, true
+	default:
+		return aliasJoinBuilder{}, nil, false
+	}
+}
@@ -0,0 +1,163 @@
+package ondatra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	LockUpdate      = "UPDATE"
+	LockNoKeyUpdate = "NO KEY UPDATE"
+	LockShare       = "SHARE"
+	LockKeyShare    = "KEY SHARE"
+
+	lockSkipLocked = "SKIP LOCKED"
+	lockNoWait     = "NOWAIT"
+)
+
+// rowLock is a row-locking clause of select, it is allowed only in transaction
+type rowLock struct {
+	strength string
+	of       []string
+	wait     string
+}
+
+// ForUpdate locks selected rows, e.g. FOR UPDATE
+func (b Builder) ForUpdate() Builder {
+	return b.lockRows(LockUpdate)
+}
+
+// ForNoKeyUpdate locks selected rows without blocking foreign keys, postgres FOR NO KEY UPDATE
+func (b Builder) ForNoKeyUpdate() Builder {
+	return b.lockRows(LockNoKeyUpdate)
+}
+
+// ForShare locks selected rows in shared mode, e.g. FOR SHARE
+func (b Builder) ForShare() Builder {
+	return b.lockRows(LockShare)
+}
+
+// ForKeyShare locks keys of selected rows in shared mode, postgres FOR KEY SHARE
+func (b Builder) ForKeyShare() Builder {
+	return b.lockRows(LockKeyShare)
+}
+
+// Of limits row lock to the tables, e.g. FOR UPDATE OF jobs
+func (b Builder) Of(tables ...string) Builder {
+	b.lock.of = append(b.lock.of, tables...)
+	return b
+}
+
+// SkipLocked skips rows locked by other transactions instead of waiting
+func (b Builder) SkipLocked() Builder {
+	b.lock.wait = lockSkipLocked
+	return b
+}
+
+// NoWait fails instead of waiting for rows locked by other transactions
+func (b Builder) NoWait() Builder {
+	b.lock.wait = lockNoWait
+	return b
+}
+
+func (b Builder) lockRows(strength string) Builder {
+	b.lock.strength = strength
+	return b
+}
+
+// lockSQL returns row-locking clause written at the end of select:
+// postgres FOR UPDATE OF t SKIP LOCKED, mysql 8 the same without key modes,
+// mysql 5.7 compatible LOCK IN SHARE MODE for plain shared lock
+func (b Builder) lockSQL() string {
+	if b.lock.strength == "" || b.command != CommandSelect {
+		return ""
+	}
+
+	strength := b.lock.strength
+	switch b.dialect {
+	case DialectSQLite, DialectSQLServer:
+		return ""
+	case DialectMySQL:
+		switch strength {
+		case LockNoKeyUpdate:
+			strength = LockUpdate
+		case LockKeyShare:
+			strength = LockShare
+		}
+		if strength == LockShare && len(b.lock.of) == 0 && b.lock.wait == "" {
+			return "LOCK IN SHARE MODE"
+		}
+	}
+
+	parts := []string{"FOR " + strength}
+	if len(b.lock.of) > 0 {
+		parts = append(parts, "OF "+strings.Join(b.lock.of, ", "))
+	}
+	if b.lock.wait != "" {
+		parts = append(parts, b.lock.wait)
+	}
+	return strings.Join(parts, " ")
+}
+
+// lockHintSQL returns sqlserver table hints written after the table, e.g. WITH (UPDLOCK, ROWLOCK, READPAST)
+func (b Builder) lockHintSQL() string {
+	if b.lock.strength == "" || b.command != CommandSelect || b.dialect != DialectSQLServer {
+		return ""
+	}
+
+	hints := []string{"UPDLOCK", "ROWLOCK"}
+	if b.lock.strength == LockShare || b.lock.strength == LockKeyShare {
+		hints = []string{"HOLDLOCK", "ROWLOCK"}
+	}
+	switch b.lock.wait {
+	case lockSkipLocked:
+		hints = append(hints, "READPAST")
+	case lockNoWait:
+		hints = append(hints, "NOWAIT")
+	}
+	return fmt.Sprintf("WITH (%s)", strings.Join(hints, ", "))
+}
+
+// ClaimRows atomically claims up to limit rows of the query for a worker of job queue.
+// Rows are locked with FOR UPDATE SKIP LOCKED, so concurrent workers get different rows,
+// updated by set expressions and scanned into dest slice by db tags.
+// Postgres runs one UPDATE ... WHERE key IN (SELECT ...) RETURNING *, other dialects run
+// select, update and select again in one transaction.
+func (b Builder) ClaimRows(ctx context.Context, query Builder, keyColumn string, limit int64, dest any, set ...Expr) error {
+	claim := query
+	claim.selectExpr = nil
+	claim = claim.Select(keyColumn).Limit(limit).ForUpdate().SkipLocked()
+
+	update := b.New().Update().SetExpr(set...)
+	update.table = query.table
+
+	if b.dialect == DialectPostgres {
+		return update.
+			WhereExpr(NewExpr(keyColumn+" IN (?)", claim)).
+			ReturningAll().
+			ExecReturningInto(ctx, dest)
+	}
+
+	return b.transaction(ctx, func(tx Builder) error {
+		claim.writerConn, claim.readerConn = tx.writerConn, nil
+
+		var keys []any
+		if err := claim.GetAllContext(ctx, &keys); err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+		in := NewExpr(fmt.Sprintf("%s IN (%s)", keyColumn, strings.TrimRight(strings.Repeat("?,", len(keys)), ",")), keys...)
+
+		update.writerConn, update.readerConn = tx.writerConn, nil
+		if _, err := update.WhereExpr(in).ExecContext(ctx); err != nil {
+			return err
+		}
+
+		claimed := tx.Select("*").WhereExpr(in)
+		claimed.table = query.table
+		return claimed.GetAllContext(ctx, dest)
+	})
+}
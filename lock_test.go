@@ -0,0 +1,124 @@
+package ondatra
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuilder_Lock(t *testing.T) {
+	jobs := NewEmptyBuilder().
+		Select("id").
+		From("jobs").
+		Where("status = ?", "queued").
+		OrderBy("id").
+		Limit(10)
+
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+	}{
+		{
+			name:        "postgres",
+			builder:     jobs.Dialect(DialectPostgres).ForUpdate().Of("jobs").SkipLocked().Suffix("-- worker"),
+			expectQuery: "SELECT id FROM jobs WHERE status = ? ORDER BY id LIMIT 10 FOR UPDATE OF jobs SKIP LOCKED -- worker",
+		}, {
+			name:        "postgres no key update",
+			builder:     jobs.Dialect(DialectPostgres).Clauses(ForNoKeyUpdate(), NoWait()),
+			expectQuery: "SELECT id FROM jobs WHERE status = ? ORDER BY id LIMIT 10 FOR NO KEY UPDATE NOWAIT",
+		}, {
+			name:        "postgres key share",
+			builder:     jobs.Dialect(DialectPostgres).ForKeyShare(),
+			expectQuery: "SELECT id FROM jobs WHERE status = ? ORDER BY id LIMIT 10 FOR KEY SHARE",
+		}, {
+			name:        "mysql",
+			builder:     jobs.Dialect(DialectMySQL).ForNoKeyUpdate().SkipLocked(),
+			expectQuery: "SELECT id FROM jobs WHERE status = ? ORDER BY id LIMIT 10 FOR UPDATE SKIP LOCKED",
+		}, {
+			name:        "mysql share",
+			builder:     jobs.Dialect(DialectMySQL).ForShare(),
+			expectQuery: "SELECT id FROM jobs WHERE status = ? ORDER BY id LIMIT 10 LOCK IN SHARE MODE",
+		}, {
+			name:        "sqlserver",
+			builder:     jobs.Dialect(DialectSQLServer).ForUpdate().SkipLocked(),
+			expectQuery: "SELECT id FROM jobs WITH (UPDLOCK, ROWLOCK, READPAST) WHERE status = ? ORDER BY id LIMIT 10",
+		}, {
+			name:        "sqlite",
+			builder:     jobs.Dialect(DialectSQLite).ForUpdate(),
+			expectQuery: "SELECT id FROM jobs WHERE status = ? ORDER BY id LIMIT 10",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, []any{"queued"}, args)
+		})
+	}
+}
+
+func TestBuilder_LockOutsideTransaction(t *testing.T) {
+	b, mock := newMockBuilder(t)
+
+	var ids []int64
+	err := b.Select("id").From("jobs").ForUpdate().GetAllContext(context.Background(), &ids)
+	assert.ErrorIs(t, err, ErrLockOutsideTransaction)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM jobs FOR UPDATE").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err = b.RunInTransaction(context.Background(), func(tx Builder) error {
+		return tx.Select("id").From("jobs").ForUpdate().GetAllContext(context.Background(), &ids)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, ids)
+}
+
+func TestBuilder_ClaimRows(t *testing.T) {
+	b, mock := newMockBuilder(t)
+
+	mock.ExpectQuery("UPDATE jobs SET status = $1 "+
+		"WHERE id IN (SELECT id FROM jobs WHERE status = $2 ORDER BY id LIMIT 2 FOR UPDATE SKIP LOCKED) RETURNING *").
+		WithArgs("running", "queued").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "running").AddRow(2, "running"))
+
+	var claimed []struct {
+		ID     int64  `db:"id"`
+		Status string `db:"status"`
+	}
+	query := b.Select("*").From("jobs").Where("status = ?", "queued").OrderBy("id")
+	err := b.ClaimRows(context.Background(), query, "id", 2, &claimed, NewExpr("status = ?", "running"))
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 2)
+	assert.Equal(t, int64(2), claimed[1].ID)
+}
+
+func TestBuilder_ClaimRowsTransaction(t *testing.T) {
+	b, mock := newMockBuilder(t)
+	b = b.Dialect(DialectMySQL)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM jobs WHERE status = $1 LIMIT 2 FOR UPDATE SKIP LOCKED").
+		WithArgs("queued").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectExec("UPDATE jobs SET status = $1 WHERE id IN ($2,$3)").
+		WithArgs("running", 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT * FROM jobs WHERE id IN ($1,$2)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectCommit()
+
+	var claimed []struct {
+		ID int64 `db:"id"`
+	}
+	query := b.Select().From("jobs").Where("status = ?", "queued")
+	err := b.ClaimRows(context.Background(), query, "id", 2, &claimed, NewExpr("status = ?", "running"))
+	assert.NoError(t, err)
+	assert.Len(t, claimed, 2)
+}
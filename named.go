@@ -0,0 +1,98 @@
+package ondatra
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// namedArguments returns lookup of named values when the raw sql has only named markers
+// and arguments are map[string]any, sql.NamedArg values or a single struct with db tags
+func namedArguments(placeholders []placeholder, args []any) (func(name string) (any, bool), bool) {
+	var hasNamed bool
+	for _, p := range placeholders {
+		switch p.kind {
+		case placeholderPositional:
+			return nil, false
+		case placeholderNamed:
+			hasNamed = true
+		}
+	}
+	if !hasNamed {
+		return nil, false
+	}
+
+	if named, ok := sqlNamedArguments(args); ok {
+		return func(name string) (any, bool) {
+			value, ok := named[name]
+			return value, ok
+		}, true
+	}
+
+	if len(args) != 1 {
+		return nil, false
+	}
+
+	switch a := args[0].(type) {
+	case map[string]any:
+		return func(name string) (any, bool) {
+			value, ok := a[name]
+			return value, ok
+		}, true
+	case Expr, driver.Valuer, time.Time, *time.Time:
+		return nil, false
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(args[0]))
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	return func(name string) (any, bool) {
+		return structFieldByColumn(v, name)
+	}, true
+}
+
+func sqlNamedArguments(args []any) (map[string]any, bool) {
+	named := make(map[string]any, len(args))
+	for _, arg := range args {
+		namedArg, ok := arg.(sql.NamedArg)
+		if !ok {
+			return nil, false
+		}
+		named[namedArg.Name] = namedArg.Value
+	}
+	return named, true
+}
+
+// structFieldByColumn finds field value by the db tag name or by the lower case field name
+func structFieldByColumn(v reflect.Value, name string) (any, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && reflect.Indirect(v.Field(i)).Kind() == reflect.Struct {
+			if value, ok := structFieldByColumn(reflect.Indirect(v.Field(i)), name); ok {
+				return value, true
+			}
+			continue
+		}
+
+		columnName := strings.Split(field.Tag.Get("db"), ",")[0]
+		if columnName == "-" {
+			continue
+		}
+		if columnName == "" {
+			columnName = strings.ToLower(field.Name)
+		}
+
+		if columnName == name {
+			return v.Field(i).Interface(), true
+		}
+	}
+	return nil, false
+}
@@ -23,6 +23,7 @@ const (
 	JoinLeft  = "LEFT"
 	JoinRight = "RIGHT"
 	JoinFull  = "FULL"
+	JoinCross = "CROSS"
 
 	ColumnCreatedAt = "created_at"
 	ColumnUpdatedAt = "updated_at"
@@ -37,7 +38,14 @@ var DebugMode = false
 type Builder struct {
 	writerConn        Connection
 	readerConn        Connection
+	readerPool        *ReaderPool
 	placeholderFormat PlaceholderFormat
+	dialect           Dialect
+	tenantGuard       *TenantGuard
+	tenantID          any
+	policies          *PolicyRegistry
+	strict            bool
+	strictTables      []Table
 
 	prefixes         []Expr   // for all
 	command          string   // for all
@@ -46,16 +54,19 @@ type Builder struct {
 	selectExpr       []Expr   // only for select
 	columns          []string // only for insert or update
 	insertValues     [][]any  // only for insert
-	returningColumns []string // only for insert or update
-	returningDest    []any    // only for insert or update
+	returningColumns []string // only for insert, update or delete
+	returningDest    [][]any  // only for insert, update or delete
 	updateValues     []Expr   // only for update
+	from             []Expr   // only for update or delete
 	joins            []Expr   // only for select
 	whereExpr        []Expr   // for all
-	groupBys         []string // only for select
+	groupBys         []Expr   // only for select
 	havingParts      []Expr   // only for select
+	windows          []Expr   // only for select
 	orderByParts     []Expr   // only for select
 	limit            int64    // only for select
 	offset           int64    // only for select
+	lock             rowLock  // only for select
 	suffixes         []Expr   // for all
 }
 
@@ -67,6 +78,7 @@ func NewEmptyBuilder() Builder {
 func NewBuilder(db *sqlx.DB) Builder {
 	return Builder{
 		writerConn: NewDB(db),
+		dialect:    DialectByDriver(db.DriverName()),
 	}
 }
 
@@ -74,19 +86,28 @@ func NewBuilderWriterReader(writerDB *sqlx.DB, readerDB *sqlx.DB) Builder {
 	return Builder{
 		writerConn: NewDB(writerDB),
 		readerConn: NewDB(readerDB),
+		dialect:    DialectByDriver(writerDB.DriverName()),
 	}
 }
 
 func NewBuilderTx(tx *sqlx.Tx) Builder {
 	return Builder{
 		writerConn: NewTx(tx),
+		dialect:    DialectByDriver(tx.DriverName()),
 	}
 }
 
 func (b Builder) New() Builder {
 	return Builder{
-		writerConn: b.writerConn,
-		readerConn: b.readerConn,
+		writerConn:   b.writerConn,
+		readerConn:   b.readerConn,
+		readerPool:   b.readerPool,
+		dialect:      b.dialect,
+		tenantGuard:  b.tenantGuard,
+		tenantID:     b.tenantID,
+		policies:     b.policies,
+		strict:       b.strict,
+		strictTables: b.strictTables,
 	}
 }
 
@@ -119,7 +140,19 @@ func (b Builder) RunInTransaction(ctx context.Context, exec func(Builder) error)
 		}
 	}()
 
-	if err = exec(NewBuilderTx(tx)); err != nil {
+	txBuilder := NewBuilderTx(tx)
+	txBuilder.writerConn = &Tx{Tx: tx, driver: connDriver(b.writerConn)}
+	if cached, ok := b.writerConn.(*CachedDB); ok {
+		txBuilder.writerConn = cached.newTx(tx)
+	}
+	if b.dialect != "" {
+		txBuilder.dialect = b.dialect
+	}
+	txBuilder.tenantGuard = b.tenantGuard
+	txBuilder.tenantID = b.tenantID
+	txBuilder.policies = b.policies
+
+	if err = exec(txBuilder); err != nil {
 		if DebugMode {
 			log.Println("Rollback transaction")
 		}
@@ -133,6 +166,23 @@ func (b Builder) RunInTransaction(ctx context.Context, exec func(Builder) error)
 	return tx.Commit()
 }
 
+// transaction runs exec in the current transaction or begins a new one
+func (b Builder) transaction(ctx context.Context, exec func(Builder) error) error {
+	if b.inTransaction() {
+		return exec(b)
+	}
+	return b.RunInTransaction(ctx, exec)
+}
+
+func (b Builder) inTransaction() bool {
+	switch b.writerConn.(type) {
+	case *Tx, *CachedTx:
+		return true
+	default:
+		return false
+	}
+}
+
 func (b Builder) Clauses(clauses ...Clause) Builder {
 	for i := range clauses {
 		b = clauses[i].Apply(b)
@@ -182,6 +232,16 @@ func (b Builder) SelectColumn(rawSQL string, args ...any) Builder {
 	return b
 }
 
+// SelectExpr use for select columns from Expr, e.g. Count(column).As("total")
+func (b Builder) SelectExpr(expr ...Expr) Builder {
+	for i := range expr {
+		if expr[i] != nil {
+			b.selectExpr = append(b.selectExpr, expr[i])
+		}
+	}
+	return b
+}
+
 func (b Builder) Options(options ...string) Builder {
 	b.options = append(b.options, options...)
 	return b
@@ -204,16 +264,64 @@ func (b Builder) Values(values ...any) Builder {
 	return b
 }
 
+// InsertMap use for insert rows of maps into the table, see ValuesMaps
+func (b Builder) InsertMap(table string, rows ...map[string]any) Builder {
+	return b.Insert().Into(table).ValuesMaps(rows)
+}
+
+// ValuesMap use for insert row of map, see ValuesMaps
+func (b Builder) ValuesMap(row map[string]any) Builder {
+	return b.ValuesMaps([]map[string]any{row})
+}
+
+// ValuesMaps use for insert rows of maps, columns are union of keys of all rows sorted by name
+// and appended to already set columns, missing values are inserted as DEFAULT
+func (b Builder) ValuesMaps(rows []map[string]any) Builder {
+	var newColumns []string
+	for _, row := range rows {
+		for column := range row {
+			if !slices.Contains(b.columns, column) && !slices.Contains(newColumns, column) {
+				newColumns = append(newColumns, column)
+			}
+		}
+	}
+	slices.Sort(newColumns)
+	columns := append(slices.Clone(b.columns), newColumns...)
+
+	insertValues := make([][]any, len(b.insertValues), len(b.insertValues)+len(rows))
+	for i, values := range b.insertValues {
+		insertValues[i] = slices.Clone(values)
+		for len(insertValues[i]) < len(columns) {
+			insertValues[i] = append(insertValues[i], NewExpr("DEFAULT"))
+		}
+	}
+	for _, row := range rows {
+		values := make([]any, len(columns))
+		for i, column := range columns {
+			value, ok := row[column]
+			if !ok {
+				value = NewExpr("DEFAULT")
+			}
+			values[i] = value
+		}
+		insertValues = append(insertValues, values)
+	}
+
+	b.columns = columns
+	b.insertValues = insertValues
+	return b
+}
+
 // Set use for update
 func (b Builder) Set(column string, value any) Builder {
-	b.updateValues = append(b.updateValues, NewExpr(fmt.Sprintf("%s = ?", column), value))
+	b.updateValues = append(b.updateValues, newAssignExpr(column, value))
 	return b
 }
 
-// SetMap use for update
+// SetMap use for update, columns are sorted by name
 func (b Builder) SetMap(clauses map[string]any) Builder {
-	for column, value := range clauses {
-		b.updateValues = append(b.updateValues, NewExpr(fmt.Sprintf("%s = ?", column), value))
+	for _, column := range sortedKeys(clauses) {
+		b.updateValues = append(b.updateValues, newAssignExpr(column, clauses[column]))
 	}
 	return b
 }
@@ -241,17 +349,17 @@ func (b Builder) StructColumns(object any) Builder {
 }
 
 func (b Builder) Table(table string) Builder {
-	b.table = NewExpr(table)
+	b.table = identExpr{kind: identTable, ident: table}
 	return b
 }
 
 func (b Builder) Into(table string) Builder {
-	b.table = NewExpr(table)
+	b.table = identExpr{kind: identTable, ident: table}
 	return b
 }
 
 func (b Builder) From(table string) Builder {
-	b.table = NewExpr(table)
+	b.table = identExpr{kind: identTable, ident: table}
 	return b
 }
 
@@ -299,7 +407,19 @@ func (b Builder) WhereExpr(expr ...Expr) Builder {
 }
 
 func (b Builder) GroupBy(groupBys ...string) Builder {
-	b.groupBys = append(b.groupBys, groupBys...)
+	for _, groupBy := range groupBys {
+		b.groupBys = append(b.groupBys, identExpr{kind: identColumn, ident: groupBy})
+	}
+	return b
+}
+
+// GroupByExpr use for group by Column[T] or Expr
+func (b Builder) GroupByExpr(expr ...Expr) Builder {
+	for i := range expr {
+		if expr[i] != nil {
+			b.groupBys = append(b.groupBys, expr[i])
+		}
+	}
 	return b
 }
 
@@ -308,9 +428,24 @@ func (b Builder) Having(rawSQL string, args ...any) Builder {
 	return b
 }
 
+func (b Builder) HavingExpr(expr ...Expr) Builder {
+	for i := range expr {
+		if expr[i] != nil {
+			b.havingParts = append(b.havingParts, expr[i])
+		}
+	}
+	return b
+}
+
+// Window define named window, e.g. WINDOW w AS (PARTITION BY ...), functions use it by WindowFunc.OverName
+func (b Builder) Window(name string, window WindowSpec) Builder {
+	b.windows = append(b.windows, NewExpr(name+" AS (?)", window))
+	return b
+}
+
 func (b Builder) OrderBy(orderBys ...string) Builder {
 	for _, orderBy := range orderBys {
-		b.orderByParts = append(b.orderByParts, NewExpr(orderBy))
+		b.orderByParts = append(b.orderByParts, identExpr{kind: identOrder, ident: orderBy})
 	}
 	return b
 }
@@ -336,6 +471,17 @@ func (b Builder) Offset(offset int64) Builder {
 	return b
 }
 
+// Returning add columns to RETURNING of insert, update or delete, use ExecReturningInto to scan them
+func (b Builder) Returning(columns ...string) Builder {
+	b.returningColumns = append(b.returningColumns, columns...)
+	return b
+}
+
+// ReturningAll add RETURNING *, columns are mapped to dest of ExecReturningInto by db tags
+func (b Builder) ReturningAll() Builder {
+	return b.Returning("*")
+}
+
 func (b Builder) Suffix(rawSQL string, args ...any) Builder {
 	b.suffixes = append(b.suffixes, NewExpr(rawSQL, args...))
 	return b
@@ -346,11 +492,69 @@ func (b Builder) PlaceholderFormat(placeholderFormat PlaceholderFormat) Builder
 	return b
 }
 
+// Dialect set SQL dialect, by default it is detected by the driver name of the connection
+func (b Builder) Dialect(dialect Dialect) Builder {
+	b.dialect = dialect
+	return b
+}
+
+// bindParts returns the builder with subqueries of all its parts replaced by the result of bind
+func (b Builder) bindParts(bind func(Builder) Builder) Builder {
+	if b.table != nil {
+		b.table = bindSubqueries(b.table, bind)
+	}
+	for _, exprs := range []*[]Expr{
+		&b.prefixes, &b.selectExpr, &b.updateValues, &b.from, &b.joins, &b.whereExpr,
+		&b.groupBys, &b.havingParts, &b.windows, &b.orderByParts, &b.suffixes,
+	} {
+		*exprs = bindSubqueriesExprs(*exprs, bind)
+	}
+
+	insertValues := make([][]any, len(b.insertValues))
+	for i := range b.insertValues {
+		insertValues[i] = bindSubqueriesArgs(b.insertValues[i], bind)
+	}
+	b.insertValues = insertValues
+
+	return b
+}
+
+// guardWhere adds conditions of tenant or policies to where, the where of the statement is grouped
+// into parentheses once, so its OR can not bypass the conditions
+func (b Builder) guardWhere(conditions ...Expr) Builder {
+	if len(conditions) == 0 {
+		return b
+	}
+	where := b.whereExpr
+	if len(where) > 0 {
+		if _, ok := where[0].(whereGroup); !ok {
+			where = []Expr{whereGroup(where)}
+		}
+	}
+	b.whereExpr = append(slices.Clone(where), conditions...)
+	return b
+}
+
 func (b Builder) ToSQL() (string, []any, error) {
+	return b.toSQL(context.Background())
+}
+
+// toSQL returns SQL with policies evaluated for the context
+func (b Builder) toSQL(ctx context.Context) (string, []any, error) {
 	var err error
 	var args []any
 	var buffer strings.Builder
 
+	if b, err = b.applyTenant(); err != nil {
+		return "", nil, err
+	}
+	if b, err = b.applyPolicies(ctx); err != nil {
+		return "", nil, err
+	}
+	if b, err = b.applyIdents(); err != nil {
+		return "", nil, err
+	}
+
 	if len(b.prefixes) > 0 {
 		if args, err = writeExprs(b.prefixes, &buffer, " ", args); err != nil {
 			return "", nil, err
@@ -383,6 +587,11 @@ func (b Builder) ToSQL() (string, []any, error) {
 				return "", nil, err
 			}
 			buffer.WriteString(" ")
+
+			if hint := b.lockHintSQL(); hint != "" {
+				buffer.WriteString(hint)
+				buffer.WriteString(" ")
+			}
 		}
 	case CommandInsert:
 		if len(b.insertValues) == 0 {
@@ -423,41 +632,20 @@ func (b Builder) ToSQL() (string, []any, error) {
 		}
 		buffer.WriteString(strings.Join(valuesStrings, ","))
 		buffer.WriteString(" ")
-
-		if len(b.returningColumns) > 0 {
-			buffer.WriteString(fmt.Sprintf(" RETURNING %s", strings.Join(b.returningColumns, ", ")))
-		}
 	case CommandUpdate:
 		if len(b.updateValues) == 0 {
 			return "", nil, NotSetValues
 		}
-		if b.table != nil {
-			if args, err = writeExpr(b.table, &buffer, args); err != nil {
-				return "", nil, err
-			}
-		}
-
-		buffer.WriteString(" SET ")
-
-		if args, err = writeExprs(b.updateValues, &buffer, ", ", args); err != nil {
+		if args, err = b.writeUpdate(&buffer, args); err != nil {
 			return "", nil, err
 		}
-		buffer.WriteString(" ")
-
-		if len(b.returningColumns) > 0 {
-			buffer.WriteString(fmt.Sprintf(" RETURNING %s", strings.Join(b.returningColumns, ", ")))
-		}
 	case CommandDelete:
-		if b.table != nil {
-			buffer.WriteString("FROM ")
-			if args, err = writeExpr(b.table, &buffer, args); err != nil {
-				return "", nil, err
-			}
-			buffer.WriteString(" ")
+		if args, err = b.writeDelete(&buffer, args); err != nil {
+			return "", nil, err
 		}
 	}
 
-	if len(b.joins) > 0 {
+	if len(b.joins) > 0 && b.command != CommandUpdate && b.command != CommandDelete {
 		if args, err = writeExprs(b.joins, &buffer, " ", args); err != nil {
 			return "", nil, err
 		}
@@ -473,7 +661,11 @@ func (b Builder) ToSQL() (string, []any, error) {
 	}
 
 	if len(b.groupBys) > 0 {
-		buffer.WriteString(fmt.Sprintf("GROUP BY %s ", strings.Join(b.groupBys, ", ")))
+		buffer.WriteString("GROUP BY ")
+		if args, err = writeExprs(b.groupBys, &buffer, ", ", args); err != nil {
+			return "", nil, err
+		}
+		buffer.WriteString(" ")
 	}
 
 	if len(b.havingParts) > 0 {
@@ -484,6 +676,14 @@ func (b Builder) ToSQL() (string, []any, error) {
 		buffer.WriteString(" ")
 	}
 
+	if len(b.windows) > 0 {
+		buffer.WriteString("WINDOW ")
+		if args, err = writeExprs(b.windows, &buffer, ", ", args); err != nil {
+			return "", nil, err
+		}
+		buffer.WriteString(" ")
+	}
+
 	if len(b.orderByParts) > 0 {
 		buffer.WriteString("ORDER BY ")
 		if args, err = writeExprs(b.orderByParts, &buffer, ", ", args); err != nil {
@@ -500,30 +700,57 @@ func (b Builder) ToSQL() (string, []any, error) {
 		buffer.WriteString(fmt.Sprintf("OFFSET %d ", b.offset))
 	}
 
+	if lock := b.lockSQL(); lock != "" {
+		buffer.WriteString(lock)
+		buffer.WriteString(" ")
+	}
+
 	if len(b.suffixes) > 0 {
 		if args, err = writeExprs(b.suffixes, &buffer, " ", args); err != nil {
 			return "", nil, err
 		}
+		buffer.WriteString(" ")
+	}
+
+	if len(b.returningColumns) > 0 && b.command != CommandSelect {
+		buffer.WriteString(fmt.Sprintf("RETURNING %s", strings.Join(b.returningColumns, ", ")))
 	}
 
 	sqlString := buffer.String()
 	if b.placeholderFormat != nil {
 		sqlString = b.placeholderFormat.ReplacePlaceholders(sqlString)
+		if f, ok := b.placeholderFormat.(NamedPlaceholderFormat); ok {
+			args = f.NamedArgs(args)
+		}
 	}
 
 	return strings.TrimSpace(sqlString), args, nil
 }
 
 func (b Builder) ToQueryWithArgs() (string, []any, error) {
+	return b.toQueryWithArgs(context.Background())
+}
+
+func (b Builder) toQueryWithArgs(ctx context.Context) (string, []any, error) {
 	if b.writerConn == nil && b.readerConn == nil {
 		return "", nil, SqlDBNotSet
 	}
 
-	query, args, err := b.ToSQL()
+	if b.lock.strength != "" && !b.inTransaction() {
+		return "", nil, ErrLockOutsideTransaction
+	}
+
+	query, args, err := b.toSQL(ctx)
 	if err != nil {
 		return "", nil, err
 	}
-	query = b.conn().Rebind(query)
+	if b.placeholderFormat == nil {
+		if b.writerConn != nil {
+			query = b.writerConn.Rebind(query)
+		} else {
+			query = b.readerConn.Rebind(query)
+		}
+	}
 
 	if DebugMode {
 		log.Println("Query:", query, "Arguments:", args)
@@ -532,6 +759,11 @@ func (b Builder) ToQueryWithArgs() (string, []any, error) {
 	return query, args, nil
 }
 
+// ToQueryWithArgsContext returns query with the tenant and policies evaluated for the context
+func (b Builder) ToQueryWithArgsContext(ctx context.Context) (string, []any, error) {
+	return b.tenantFromContext(ctx).toQueryWithArgs(ctx)
+}
+
 func (b Builder) Get(dest any) error {
 	query, args, err := b.ToQueryWithArgs()
 	if err != nil {
@@ -541,11 +773,11 @@ func (b Builder) Get(dest any) error {
 }
 
 func (b Builder) GetContext(ctx context.Context, dest any) error {
-	query, args, err := b.ToQueryWithArgs()
+	query, args, err := b.ToQueryWithArgsContext(ctx)
 	if err != nil {
 		return err
 	}
-	return b.conn().GetContext(ctx, dest, query, args...)
+	return b.connContext(ctx).GetContext(ctx, dest, query, args...)
 }
 
 func (b Builder) GetAll(dest any) error {
@@ -557,11 +789,11 @@ func (b Builder) GetAll(dest any) error {
 }
 
 func (b Builder) GetAllContext(ctx context.Context, dest any) error {
-	query, args, err := b.ToQueryWithArgs()
+	query, args, err := b.ToQueryWithArgsContext(ctx)
 	if err != nil {
 		return err
 	}
-	return b.conn().SelectContext(ctx, dest, query, args...)
+	return b.connContext(ctx).SelectContext(ctx, dest, query, args...)
 }
 
 func (b Builder) Exec() (sql.Result, error) {
@@ -573,11 +805,11 @@ func (b Builder) Exec() (sql.Result, error) {
 }
 
 func (b Builder) ExecContext(ctx context.Context) (sql.Result, error) {
-	query, args, err := b.ToQueryWithArgs()
+	query, args, err := b.ToQueryWithArgsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return b.conn().ExecContext(ctx, query, args...)
+	return b.connContext(ctx).ExecContext(ctx, query, args...)
 }
 
 func (b Builder) ExecRaw(query string, args ...any) (sql.Result, error) {
@@ -593,11 +825,11 @@ func (b Builder) Query() (*sql.Rows, error) {
 }
 
 func (b Builder) QueryContext(ctx context.Context) (*sql.Rows, error) {
-	query, args, err := b.ToQueryWithArgs()
+	query, args, err := b.ToQueryWithArgsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return b.conn().QueryContext(ctx, query, args...)
+	return b.connContext(ctx).QueryContext(ctx, query, args...)
 }
 
 func (b Builder) QueryRow() (*sql.Row, error) {
@@ -609,15 +841,16 @@ func (b Builder) QueryRow() (*sql.Row, error) {
 }
 
 func (b Builder) QueryRowContext(ctx context.Context) (*sql.Row, error) {
-	query, args, err := b.ToQueryWithArgs()
+	query, args, err := b.ToQueryWithArgsContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return b.conn().QueryRowContext(ctx, query, args...), nil
+	return b.connContext(ctx).QueryRowContext(ctx, query, args...), nil
 }
 
+// ExecReturning executes statement and scans RETURNING columns into fields of StructColumns objects
 func (b Builder) ExecReturning() error {
-	if len(b.returningColumns) == 0 {
+	if len(b.returningColumns) == 0 || len(b.returningDest) == 0 {
 		if _, err := b.Exec(); err != nil {
 			return err
 		}
@@ -629,73 +862,162 @@ func (b Builder) ExecReturning() error {
 		return err
 	}
 
-	return b.conn().QueryRow(query, args...).Scan(b.returningDest...)
+	rows, err := b.conn().Query(query, args...)
+	if err != nil {
+		return err
+	}
+	return scanReturning(rows, b.returningDest)
 }
 
+// ExecReturningContext executes statement and scans RETURNING columns into fields of StructColumns objects
 func (b Builder) ExecReturningContext(ctx context.Context) error {
-	if len(b.returningColumns) == 0 {
+	if len(b.returningColumns) == 0 || len(b.returningDest) == 0 {
 		if _, err := b.ExecContext(ctx); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	query, args, err := b.ToQueryWithArgs()
+	query, args, err := b.ToQueryWithArgsContext(ctx)
 	if err != nil {
 		return err
 	}
 
-	return b.conn().QueryRowContext(ctx, query, args...).Scan(b.returningDest...)
+	rows, err := b.connContext(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return scanReturning(rows, b.returningDest)
+}
+
+// ExecReturningInto executes statement and scans RETURNING rows into dest by db tags,
+// dest is a pointer to struct for one row or a pointer to slice for many rows
+func (b Builder) ExecReturningInto(ctx context.Context, dest any) error {
+	if len(b.returningColumns) == 0 {
+		return ErrReturningNotSet
+	}
+
+	query, args, err := b.ToQueryWithArgsContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if t := reflect.TypeOf(dest); t.Kind() == reflect.Pointer &&
+		t.Elem().Kind() == reflect.Slice && t.Elem().Elem().Kind() != reflect.Uint8 {
+		return b.connContext(ctx).SelectContext(ctx, dest, query, args...)
+	}
+	return b.connContext(ctx).GetContext(ctx, dest, query, args...)
+}
+
+func scanReturning(rows *sql.Rows, dest [][]any) error {
+	defer rows.Close()
+
+	var scanned int
+	for rows.Next() {
+		if scanned == len(dest) {
+			break
+		}
+		if err := rows.Scan(dest[scanned]...); err != nil {
+			return err
+		}
+		scanned++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if scanned == 0 {
+		return sql.ErrNoRows
+	}
+	return rows.Close()
 }
 
 func (b Builder) Raw(ctx context.Context, dest any, query string, args ...any) error {
-	return b.conn().SelectContext(ctx, dest, query, args)
+	return b.connContext(ctx).SelectContext(ctx, dest, query, args)
 }
 
 func (b Builder) insertStructColumns(object any) Builder {
-	if len(b.insertValues) == 0 {
-		b.insertValues = make([][]any, 1)
+	v := reflect.Indirect(reflect.ValueOf(object))
+	if v.Kind() != reflect.Slice {
+		return b.insertStructRows([]reflect.Value{v})
 	}
 
-	v := reflect.Indirect(reflect.ValueOf(object))
+	rows := make([]reflect.Value, v.Len())
+	for i := range rows {
+		rows[i] = reflect.Indirect(v.Index(i))
+	}
+	return b.insertStructRows(rows)
+}
 
-	for i := 0; i < v.NumField(); i++ {
-		dbTags := strings.Split(v.Type().Field(i).Tag.Get("db"), ",")
+// insertStructRows set columns and values of rows, columns with default tag are returned if they are zero,
+// in several rows zero default values are inserted as DEFAULT when other rows have values
+func (b Builder) insertStructRows(rows []reflect.Value) Builder {
+	if len(rows) == 0 {
+		return b
+	}
+	for len(b.insertValues) < len(rows) {
+		b.insertValues = append(b.insertValues, nil)
+	}
+
+	t := rows[0].Type()
+	for i := 0; i < t.NumField(); i++ {
+		dbTags := strings.Split(t.Field(i).Tag.Get("db"), ",")
 		if len(dbTags) == 0 || !slices.Contains(dbTags, modelTagColumn) {
 			continue
 		}
 		columnName := dbTags[0]
 
-		field := v.Field(i)
-		value := field.Interface()
-
+		var defaultRows []bool
 		if slices.Contains(dbTags, modelTagDefault) {
-			var skip bool
-			if valuer, ok := value.(driver.Valuer); ok {
-				valuerValue, err := valuer.Value()
-				if err == nil && (valuerValue == nil || valuerValue == reflect.Zero(reflect.TypeOf(valuerValue)).Interface()) {
-					skip = true
-				}
-			} else {
-				if value == reflect.Zero(field.Type()).Interface() {
-					skip = true
+			defaultRows = make([]bool, len(rows))
+			var defaults int
+			for r := range rows {
+				if defaultRows[r] = isZeroValue(rows[r].Field(i)); defaultRows[r] {
+					defaults++
 				}
 			}
 
-			if skip {
+			if defaults > 0 {
 				b.returningColumns = append(b.returningColumns, columnName)
-				b.returningDest = append(b.returningDest, field.Addr().Interface())
+				for r := range rows {
+					b = b.addReturningDest(r, rows[r].Field(i).Addr().Interface())
+				}
+			}
+			if defaults == len(rows) {
 				continue
 			}
 		}
 
 		b.columns = append(b.columns, columnName)
-		b.insertValues[0] = append(b.insertValues[0], value)
+		for r := range rows {
+			if defaultRows != nil && defaultRows[r] {
+				b.insertValues[r] = append(b.insertValues[r], NewExpr("DEFAULT"))
+				continue
+			}
+			b.insertValues[r] = append(b.insertValues[r], rows[r].Field(i).Interface())
+		}
 	}
 
 	return b
 }
 
+func (b Builder) addReturningDest(row int, dest any) Builder {
+	for len(b.returningDest) <= row {
+		b.returningDest = append(b.returningDest, nil)
+	}
+	b.returningDest[row] = append(b.returningDest[row], dest)
+	return b
+}
+
+// isZeroValue reports whether the field or its driver.Valuer value is zero
+func isZeroValue(field reflect.Value) bool {
+	value := field.Interface()
+	if valuer, ok := value.(driver.Valuer); ok {
+		valuerValue, err := valuer.Value()
+		return err == nil && (valuerValue == nil || reflect.ValueOf(valuerValue).IsZero())
+	}
+	return field.IsZero()
+}
+
 func (b Builder) updateStructColumns(object any) Builder {
 	v := reflect.Indirect(reflect.ValueOf(object))
 
@@ -726,13 +1048,13 @@ func (b Builder) updateStructColumns(object any) Builder {
 		if columnName == ColumnUpdatedAt {
 			b.updateValues = append(b.updateValues, NewExpr(fmt.Sprintf("%s = DEFAULT", columnName)))
 			b.returningColumns = append(b.returningColumns, columnName)
-			b.returningDest = append(b.returningDest, field.Addr().Interface())
+			b = b.addReturningDest(0, field.Addr().Interface())
 			continue
 		}
 
 		if valueString, ok := value.(string); ok && strings.EqualFold(valueString, "DEFAULT") {
 			b.returningColumns = append(b.returningColumns, columnName)
-			b.returningDest = append(b.returningDest, field.Addr().Interface())
+			b = b.addReturningDest(0, field.Addr().Interface())
 		}
 
 		b.updateValues = append(b.updateValues, NewExpr(fmt.Sprintf("%s = ?", columnName), value))
@@ -745,9 +1067,15 @@ func (b Builder) updateStructColumns(object any) Builder {
 	return b
 }
 
-func (b Builder) conn() Connection {
-	if b.command == CommandSelect && b.readerConn != nil {
-		return b.readerConn
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
-	return b.writerConn
+	slices.Sort(keys)
+	return keys
+}
+
+func (b Builder) conn() Connection {
+	return b.connContext(context.Background())
 }
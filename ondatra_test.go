@@ -47,6 +47,28 @@ func TestBuilder_Insert(t *testing.T) {
 				),
 			expectQuery: "INSERT INTO table2 (field1) VALUES (SELECT field1 FROM table1 WHERE field1 = ?)",
 			expectArgs:  []any{1},
+		}, {
+			name:        "insert map",
+			builder:     NewEmptyBuilder().InsertMap("a", map[string]any{"c": 3, "b": 2, "a": NewExpr("? + 1", 1)}),
+			expectQuery: "INSERT INTO a (a, b, c) VALUES (? + 1,?,?)",
+			expectArgs:  []any{1, 2, 3},
+		}, {
+			name: "insert maps with missing keys",
+			builder: NewEmptyBuilder().
+				Insert().
+				Into("a").
+				Columns("id").
+				Values(1).
+				ValuesMaps([]map[string]any{{"id": 2, "name": "x"}, {"id": 3, "email": "y"}}).
+				ValuesMap(map[string]any{"name": "z"}),
+			expectQuery: "INSERT INTO a (id, email, name) VALUES " +
+				"(?,DEFAULT,DEFAULT),(?,DEFAULT,?),(?,?,DEFAULT),(DEFAULT,DEFAULT,?)",
+			expectArgs: []any{1, 2, "x", 3, "y", "z"},
+		}, {
+			name:        "insert maps columns do not depend on row order",
+			builder:     NewEmptyBuilder().InsertMap("a", map[string]any{"name": "x"}, map[string]any{"email": "y"}),
+			expectQuery: "INSERT INTO a (email, name) VALUES (DEFAULT,?),(?,DEFAULT)",
+			expectArgs:  []any{"x", "y"},
 		},
 	}
 
@@ -89,6 +111,11 @@ func TestBuilder_Update(t *testing.T) {
 				"c3 = ? " +
 				"WHERE d = ? ORDER BY e LIMIT 4 OFFSET 5 RETURNING ?",
 			expectArgs: []any{0, 1, 2, "foo", "bar", nil, 3, 6},
+		}, {
+			name:        "update set map sorted",
+			builder:     NewEmptyBuilder().Update().Table("a").SetMap(map[string]any{"d": 4, "b": 2, "c": 3, "a": 1}).Where("id = ?", 5),
+			expectQuery: "UPDATE a SET a = ?, b = ?, c = ?, d = ? WHERE id = ?",
+			expectArgs:  []any{1, 2, 3, 4, 5},
 		},
 	}
 
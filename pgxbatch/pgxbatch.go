@@ -0,0 +1,62 @@
+// Package pgxbatch adapts pgx batch to ondatra.BatchExecutor, statements of a batch are sent
+// in one round trip and run in an implicit transaction of postgres.
+package pgxbatch
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stepanbukhtii/ondatra"
+)
+
+// Sender sends pgx batch, it is *pgx.Conn, pgx.Tx or *pgxpool.Pool
+type Sender interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// Executor is ondatra.BatchExecutor over pgx batch
+type Executor struct {
+	sender Sender
+}
+
+func New(sender Sender) Executor {
+	return Executor{sender: sender}
+}
+
+// ExecBatch queues queries into one pgx batch, statements after a failed one get its error
+// because postgres aborts the implicit transaction
+func (e Executor) ExecBatch(ctx context.Context, queries []ondatra.BatchQuery) ([]ondatra.BatchResult, error) {
+	batch := &pgx.Batch{}
+	for _, query := range queries {
+		batch.Queue(query.SQL, query.Args...)
+	}
+
+	batchResults := e.sender.SendBatch(ctx, batch)
+
+	results := make([]ondatra.BatchResult, len(queries))
+	var failed error
+	for i := range results {
+		tag, err := batchResults.Exec()
+		if err != nil {
+			if failed == nil {
+				failed = err
+			}
+			results[i] = ondatra.BatchResult{RowsAffected: -1, Err: err}
+			continue
+		}
+		results[i] = ondatra.BatchResult{RowsAffected: tag.RowsAffected()}
+	}
+
+	if err := batchResults.Close(); err != nil && failed == nil {
+		return results, err
+	}
+	if failed != nil {
+		for i := range results {
+			if results[i].Err == nil {
+				results[i].Err = errors.Join(ondatra.ErrBatchAborted, failed)
+			}
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,64 @@
+package pgxbatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stepanbukhtii/ondatra"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSender struct {
+	batch   *pgx.Batch
+	results []error
+}
+
+func (s *fakeSender) SendBatch(_ context.Context, b *pgx.Batch) pgx.BatchResults {
+	s.batch = b
+	return &fakeResults{errs: s.results}
+}
+
+type fakeResults struct {
+	pgx.BatchResults
+	errs []error
+	next int
+}
+
+func (r *fakeResults) Exec() (pgconn.CommandTag, error) {
+	err := r.errs[r.next]
+	r.next++
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return pgconn.NewCommandTag("UPDATE 2"), nil
+}
+
+func (r *fakeResults) Close() error {
+	return nil
+}
+
+func TestExecutor_ExecBatch(t *testing.T) {
+	b := ondatra.NewEmptyBuilder().PlaceholderFormat(ondatra.Dollar)
+	batch := b.Batch(
+		b.Update().Table("users").Set("name", "a").Where("id = ?", 1),
+		b.Update().Table("users").Set("name", "b").Where("id = ?", 2),
+	)
+
+	sender := &fakeSender{results: []error{nil, nil}}
+	results, err := batch.Executor(New(sender)).ExecContext(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []ondatra.BatchResult{{RowsAffected: 2}, {RowsAffected: 2}}, results)
+	assert.Equal(t, 2, sender.batch.Len())
+	assert.Equal(t, "UPDATE users SET name = $1 WHERE id = $2", sender.batch.QueuedQueries[0].SQL)
+	assert.Equal(t, []any{"b", 2}, sender.batch.QueuedQueries[1].Arguments)
+
+	failed := errors.New("check violation")
+	sender = &fakeSender{results: []error{failed, failed}}
+	results, err = batch.Executor(New(sender)).ExecContext(context.Background())
+	assert.ErrorIs(t, err, failed)
+	assert.ErrorIs(t, results[0].Err, failed)
+	assert.Equal(t, int64(-1), results[1].RowsAffected)
+}
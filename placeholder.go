@@ -1,15 +1,28 @@
 package ondatra
 
 import (
+	"database/sql"
 	"strconv"
 	"strings"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type PlaceholderFormat interface {
 	ReplacePlaceholders(sql string) string
 }
 
+// NamedPlaceholderFormat is a PlaceholderFormat which requires arguments passed as sql.NamedArg
+type NamedPlaceholderFormat interface {
+	PlaceholderFormat
+	NamedArgs(args []any) []any
+}
+
 var (
+	// Question is a PlaceholderFormat instance that leaves placeholders as
+	// question marks.
+	Question = questionPlaceholderFormat{}
+
 	// Dollar is a PlaceholderFormat instance that replaces placeholders with
 	// dollar-prefixed positional placeholders (e.g. $1, $2, $3).
 	Dollar = stringPlaceholderFormat("$")
@@ -21,38 +34,129 @@ var (
 	// AtP is a PlaceholderFormat instance that replaces placeholders with
 	// "@p"-prefixed positional placeholders (e.g. @p1, @p2, @p3).
 	AtP = stringPlaceholderFormat("@p")
+
+	// NamedColon is a NamedPlaceholderFormat instance that replaces placeholders with
+	// colon-prefixed named placeholders (e.g. :arg1, :arg2, :arg3).
+	NamedColon = namedPlaceholderFormat(":")
+
+	// NamedAt is a NamedPlaceholderFormat instance that replaces placeholders with
+	// "@"-prefixed named placeholders (e.g. @arg1, @arg2, @arg3).
+	NamedAt = namedPlaceholderFormat("@")
 )
 
+const namedPlaceholderPrefix = "arg"
+
+type questionPlaceholderFormat struct{}
+
+func (questionPlaceholderFormat) ReplacePlaceholders(sql string) string {
+	return sql
+}
+
 type stringPlaceholderFormat string
 
 func (s stringPlaceholderFormat) ReplacePlaceholders(sql string) string {
 	var buffer strings.Builder
-	var i int
-	for {
-		p := strings.Index(sql, "?")
-		if p == -1 {
-			break
-		}
-
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buffer.WriteString(sql[:p])
+	var last, i int
+	for _, p := range scanPlaceholders(sql) {
+		switch p.kind {
+		case placeholderEscaped: // escape ?? => ?
+			buffer.WriteString(sql[last:p.start])
 			buffer.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
-			sql = sql[p+2:]
-		} else {
+		case placeholderPositional:
 			i++
 
-			buffer.WriteString(sql[:p])
+			buffer.WriteString(sql[last:p.start])
 			buffer.WriteString(string(s))
 			buffer.WriteString(strconv.Itoa(i))
+		default:
+			continue
+		}
+		last = p.end
+	}
+
+	buffer.WriteString(sql[last:])
+
+	return buffer.String()
+}
+
+type namedPlaceholderFormat string
 
-			sql = sql[p+1:]
+func (s namedPlaceholderFormat) ReplacePlaceholders(sql string) string {
+	return stringPlaceholderFormat(string(s) + namedPlaceholderPrefix).ReplacePlaceholders(sql)
+}
+
+func (s namedPlaceholderFormat) NamedArgs(args []any) []any {
+	namedArgs := make([]any, len(args))
+	for i := range args {
+		namedArgs[i] = sql.Named(namedPlaceholderPrefix+strconv.Itoa(i+1), args[i])
+	}
+	return namedArgs
+}
+
+// rebind replaces placeholders for the sqlx bind type of the driver
+func rebind(bindType int, query string) string {
+	switch bindType {
+	case sqlx.DOLLAR:
+		return Dollar.ReplacePlaceholders(query)
+	case sqlx.NAMED:
+		return NamedColon.ReplacePlaceholders(query)
+	case sqlx.AT:
+		return AtP.ReplacePlaceholders(query)
+	default:
+		return query
+	}
+}
+
+// InlineArgs replaces placeholders of any supported format with argument values,
+// the result is only for logging and must never be executed
+func InlineArgs(sql string, args []any) string {
+	return inlineArgs(sql, args, Dialect(""))
+}
+
+func inlineArgs(sql string, args []any, dialect Dialect) string {
+	var buffer strings.Builder
+	var last, n int
+	for _, p := range scanPlaceholders(sql) {
+		index := -1
+		switch p.kind {
+		case placeholderEscaped:
+			buffer.WriteString(sql[last:p.start])
+			buffer.WriteString("?")
+			last = p.end
+			continue
+		case placeholderPositional:
+			index = n
+			n++
+		case placeholderNumbered:
+			index = placeholderIndex(p.name, "")
+		case placeholderNamed:
+			if index = placeholderIndex(p.name, namedPlaceholderPrefix); index < 0 {
+				index = placeholderIndex(p.name, "p")
+			}
 		}
+		if index < 0 || index >= len(args) {
+			continue
+		}
+
+		buffer.WriteString(sql[last:p.start])
+		buffer.WriteString(dialect.Literal(args[index]))
+		last = p.end
 	}
 
-	buffer.WriteString(sql)
+	buffer.WriteString(sql[last:])
 
 	return buffer.String()
 }
+
+// placeholderIndex returns zero based argument index of numbered placeholder name or -1
+func placeholderIndex(name, prefix string) int {
+	number, ok := strings.CutPrefix(name, prefix)
+	if !ok || number == "" || skipDigits(number, 0) != len(number) {
+		return -1
+	}
+	i, err := strconv.Atoi(number)
+	if err != nil || i == 0 {
+		return -1
+	}
+	return i - 1
+}
@@ -1,8 +1,11 @@
 package ondatra
 
 import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestStringPlaceholderFormat_ReplacePlaceholders(t *testing.T) {
@@ -23,6 +26,18 @@ func TestStringPlaceholderFormat_ReplacePlaceholders(t *testing.T) {
 			name:        "atP placeholder",
 			placeholder: AtP,
 			expect:      "SELECT test WHERE x = @p1 AND y = @p2",
+		}, {
+			name:        "question placeholder",
+			placeholder: Question,
+			expect:      "SELECT test WHERE x = ? AND y = ?",
+		}, {
+			name:        "named colon placeholder",
+			placeholder: NamedColon,
+			expect:      "SELECT test WHERE x = :arg1 AND y = :arg2",
+		}, {
+			name:        "named at placeholder",
+			placeholder: NamedAt,
+			expect:      "SELECT test WHERE x = @arg1 AND y = @arg2",
 		},
 	}
 
@@ -33,3 +48,71 @@ func TestStringPlaceholderFormat_ReplacePlaceholders(t *testing.T) {
 		})
 	}
 }
+
+func TestStringPlaceholderFormat_ReplacePlaceholdersLiterals(t *testing.T) {
+	sql := `SELECT 'what?', "col?" FROM t WHERE a = ? -- b = ?` + "\n" +
+		`AND c ?? 'key' AND d ?| array['x'] AND e = $$?$$ AND f = ?`
+	expect := `SELECT 'what?', "col?" FROM t WHERE a = $1 -- b = ?` + "\n" +
+		`AND c ? 'key' AND d ?| array['x'] AND e = $$?$$ AND f = $2`
+	assert.Equal(t, expect, Dollar.ReplacePlaceholders(sql))
+}
+
+func TestNamedPlaceholderFormat_NamedArgs(t *testing.T) {
+	query, args, err := NewEmptyBuilder().
+		Select("a").
+		From("t").
+		Where("b = ? AND c = ?", 1, "x").
+		PlaceholderFormat(NamedAt).
+		ToSQL()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = @arg1 AND c = @arg2", query)
+	assert.Equal(t, []any{sql.Named("arg1", 1), sql.Named("arg2", "x")}, args)
+}
+
+func TestRebind(t *testing.T) {
+	query := "SELECT 'a?' FROM t WHERE b = ? AND c = ?"
+	assert.Equal(t, "SELECT 'a?' FROM t WHERE b = $1 AND c = $2", rebind(sqlx.DOLLAR, query))
+	assert.Equal(t, "SELECT 'a?' FROM t WHERE b = :arg1 AND c = :arg2", rebind(sqlx.NAMED, query))
+	assert.Equal(t, "SELECT 'a?' FROM t WHERE b = @p1 AND c = @p2", rebind(sqlx.AT, query))
+	assert.Equal(t, query, rebind(sqlx.QUESTION, query))
+}
+
+func TestInlineArgs(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	args := []any{1, "it's", nil, true, date}
+	expect := "SELECT 'a?' FROM t WHERE a = 1 AND b = 'it''s' AND c = NULL AND d = TRUE AND e = '2024-01-02T03:04:05Z'"
+
+	var tests = []struct {
+		name string
+		sql  string
+	}{
+		{
+			name: "question",
+			sql:  "SELECT 'a?' FROM t WHERE a = ? AND b = ? AND c = ? AND d = ? AND e = ?",
+		}, {
+			name: "dollar",
+			sql:  "SELECT 'a?' FROM t WHERE a = $1 AND b = $2 AND c = $3 AND d = $4 AND e = $5",
+		}, {
+			name: "colon",
+			sql:  "SELECT 'a?' FROM t WHERE a = :1 AND b = :2 AND c = :3 AND d = :4 AND e = :5",
+		}, {
+			name: "atP",
+			sql:  "SELECT 'a?' FROM t WHERE a = @p1 AND b = @p2 AND c = @p3 AND d = @p4 AND e = @p5",
+		}, {
+			name: "named",
+			sql:  "SELECT 'a?' FROM t WHERE a = :arg1 AND b = @arg2 AND c = :arg3 AND d = :arg4 AND e = :arg5",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, expect, InlineArgs(test.sql, args))
+		})
+	}
+}
+
+func TestInlineArgsArraySlice(t *testing.T) {
+	sql := "SELECT arr[1:2], arr[:n] FROM t WHERE a = :1 AND b = arr[2:3][1]"
+	expect := "SELECT arr[1:2], arr[:n] FROM t WHERE a = 7 AND b = arr[2:3][1]"
+	assert.Equal(t, expect, InlineArgs(sql, []any{7, 8, 9}))
+}
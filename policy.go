@@ -0,0 +1,193 @@
+package ondatra
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Policy is a row level authorization rule of a table, it returns conditions added to statements of the table
+// for the command or an error wrapping ErrPolicyDenied to refuse the statement
+type Policy interface {
+	Apply(ctx context.Context, command string) ([]Expr, error)
+}
+
+// PolicyFunc is an adapter to use a function as Policy
+type PolicyFunc func(ctx context.Context, command string) ([]Expr, error)
+
+func (f PolicyFunc) Apply(ctx context.Context, command string) ([]Expr, error) {
+	return f(ctx, command)
+}
+
+// PolicyRegistry keeps policies per table
+type PolicyRegistry struct {
+	mu       sync.RWMutex
+	policies map[string][]Policy
+}
+
+func NewPolicyRegistry() *PolicyRegistry {
+	return &PolicyRegistry{
+		policies: make(map[string][]Policy),
+	}
+}
+
+// Register adds policies of the table, schema of the table is ignored, e.g. public.users is users
+func (r *PolicyRegistry) Register(table string, policies ...Policy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	table = unqualifiedTable(table)
+	r.policies[table] = append(r.policies[table], policies...)
+}
+
+// Conditions returns conditions of all policies of the table, the first denial is returned as error
+func (r *PolicyRegistry) Conditions(ctx context.Context, table, command string) ([]Expr, error) {
+	r.mu.RLock()
+	policies := r.policies[unqualifiedTable(table)]
+	r.mu.RUnlock()
+
+	var conditions []Expr
+	for _, policy := range policies {
+		exprs, err := policy.Apply(ctx, command)
+		if err != nil {
+			return nil, fmt.Errorf("policy of table %s: %w", table, err)
+		}
+		for i := range exprs {
+			if exprs[i] != nil {
+				conditions = append(conditions, NewExpr("(?)", exprs[i]))
+			}
+		}
+	}
+	return conditions, nil
+}
+
+func (r *PolicyRegistry) registered(table string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.policies[unqualifiedTable(table)]
+	return ok
+}
+
+// rawJoin returns error if raw join SQL joins a table with policies or can not be parsed
+func (r *PolicyRegistry) rawJoin(rawSQL string) error {
+	refs, ok := parseTableRefs(rawSQL)
+	if !ok {
+		return fmt.Errorf("%w: parenthesised raw SQL %s", ErrPolicyDenied, rawSQL)
+	}
+	for _, ref := range refs {
+		if ref.name != "" && r.registered(ref.name) {
+			return fmt.Errorf("%w: table %s joined by raw SQL", ErrPolicyDenied, unqualifiedTable(ref.name))
+		}
+	}
+	return nil
+}
+
+// Policies enables policies of the registry for statements and subqueries of the builder
+func (b Builder) Policies(registry *PolicyRegistry) Builder {
+	b.policies = registry
+	return b
+}
+
+// ToSQLContext returns SQL with policies and tenant evaluated for the context
+func (b Builder) ToSQLContext(ctx context.Context) (string, []any, error) {
+	return b.tenantFromContext(ctx).toSQL(ctx)
+}
+
+// applyPolicies adds conditions of policies to where of select, update and delete and to ON of join expressions,
+// conditions are not applied to insert, only its denial is. Raw joins of tables with policies are refused.
+func (b Builder) applyPolicies(ctx context.Context) (Builder, error) {
+	if b.policies == nil {
+		return b, nil
+	}
+	b, err := b.bindPolicies(ctx)
+	if err != nil {
+		return b, err
+	}
+
+	tables := []Expr{b.table}
+	if b.command == CommandUpdate || b.command == CommandDelete {
+		tables = append(tables, b.from...)
+	}
+
+	var where []Expr
+	for _, table := range tables {
+		if table == nil {
+			continue
+		}
+		refs, ok := parseTableRefs(tableSQL(table))
+		if !ok {
+			return b, fmt.Errorf("%w: parenthesised raw SQL %s", ErrPolicyDenied, tableSQL(table))
+		}
+		for _, ref := range refs {
+			if ref.name == "" {
+				continue
+			}
+			if ref.joined && b.policies.registered(ref.name) {
+				return b, fmt.Errorf("%w: table %s joined by raw SQL", ErrPolicyDenied, unqualifiedTable(ref.name))
+			}
+			conditions, err := b.policies.Conditions(ctx, ref.name, b.command)
+			if err != nil {
+				return b, err
+			}
+			if b.command != CommandInsert {
+				where = append(where, conditions...)
+			}
+		}
+	}
+	b = b.guardWhere(where...)
+
+	b.joins = slices.Clone(b.joins)
+	for i, join := range b.joins {
+		aliasJoin, joinConditions, ok := asAliasJoin(join)
+		if !ok {
+			if err := b.policies.rawJoin(tableSQL(join)); err != nil {
+				return b, err
+			}
+			continue
+		}
+		conditions, err := b.policies.Conditions(ctx, aliasJoin.table.name, b.command)
+		if err != nil {
+			return b, err
+		}
+		if len(conditions) > 0 {
+			b.joins[i] = guardedJoin{join: aliasJoin, conditions: append(slices.Clone(joinConditions), conditions...)}
+		}
+	}
+
+	return b, nil
+}
+
+// bindPolicies applies tenant and policies of the context to subqueries, including subqueries of case, aggregate
+// and window expressions, subqueries without policies get policies of the builder. Subqueries are rendered
+// without the context, so their guard and policies are dropped once applied.
+func (b Builder) bindPolicies(ctx context.Context) (Builder, error) {
+	policies := b.policies
+	var err error
+	b = b.bindParts(func(subquery Builder) Builder {
+		if subquery.policies == nil {
+			subquery.policies = policies
+		}
+		subquery = subquery.tenantFromContext(ctx)
+
+		var bindErr error
+		if subquery, bindErr = subquery.applyTenant(); bindErr == nil {
+			subquery, bindErr = subquery.applyPolicies(ctx)
+		}
+		if bindErr != nil && err == nil {
+			err = bindErr
+		}
+		subquery.tenantGuard, subquery.policies = nil, nil
+		return subquery
+	})
+	return b, err
+}
+
+func toArgs(exprs []Expr) []any {
+	args := make([]any, len(exprs))
+	for i := range exprs {
+		args[i] = exprs[i]
+	}
+	return args
+}
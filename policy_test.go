@@ -0,0 +1,184 @@
+package ondatra
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type roleContextKey struct{}
+
+func TestPolicy(t *testing.T) {
+	policies := NewPolicyRegistry()
+	policies.Register("tickets", PolicyFunc(func(ctx context.Context, command string) ([]Expr, error) {
+		switch ctx.Value(roleContextKey{}) {
+		case "admin":
+			return nil, nil
+		case "support":
+			if command == CommandDelete {
+				return nil, ErrPolicyDenied
+			}
+			return []Expr{NewExpr("region = ? OR region IS NULL", "eu")}, nil
+		default:
+			return nil, ErrPolicyDenied
+		}
+	}))
+	policies.Register("users", PolicyFunc(func(ctx context.Context, command string) ([]Expr, error) {
+		return []Expr{NewExpr("users.active")}, nil
+	}))
+
+	admin := context.WithValue(context.Background(), roleContextKey{}, "admin")
+	support := context.WithValue(context.Background(), roleContextKey{}, "support")
+	builder := NewEmptyBuilder().Policies(policies)
+	users := NewTable("users", []string{"id"})
+
+	var tests = []struct {
+		name        string
+		ctx         context.Context
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+		expectErr   error
+	}{
+		{
+			name:        "admin",
+			ctx:         admin,
+			builder:     builder.Select("id").From("tickets").Where("status = ?", "open"),
+			expectQuery: "SELECT id FROM tickets WHERE status = ?",
+			expectArgs:  []any{"open"},
+		}, {
+			name:        "support",
+			ctx:         support,
+			builder:     builder.Select("id").From("tickets").Where("status = ?", "open"),
+			expectQuery: "SELECT id FROM tickets WHERE (status = ?) AND (region = ? OR region IS NULL)",
+			expectArgs:  []any{"open", "eu"},
+		}, {
+			name:      "support delete",
+			ctx:       support,
+			builder:   builder.Delete().From("tickets").Where("id = ?", 1),
+			expectErr: ErrPolicyDenied,
+		}, {
+			name:      "no role",
+			ctx:       context.Background(),
+			builder:   builder.Select("id").From("tickets"),
+			expectErr: ErrPolicyDenied,
+		}, {
+			name: "subquery",
+			ctx:  support,
+			builder: builder.Select("id").From("customers").
+				WhereExpr(Exists(NewEmptyBuilder().Select("1").From("tickets").Where("tickets.customer_id = customers.id"))),
+			expectQuery: "SELECT id FROM customers WHERE EXISTS (SELECT 1 FROM tickets " +
+				"WHERE (tickets.customer_id = customers.id) AND (region = ? OR region IS NULL))",
+			expectArgs: []any{"eu"},
+		}, {
+			name:      "subquery denied",
+			ctx:       context.Background(),
+			builder:   builder.Select("id").From("customers").WhereExpr(Exists(NewEmptyBuilder().Select("1").From("tickets"))),
+			expectErr: ErrPolicyDenied,
+		}, {
+			name:        "where with or",
+			ctx:         support,
+			builder:     builder.Select("id").From("public.tickets").Where("status = ? OR status = ?", "open", "new"),
+			expectQuery: "SELECT id FROM public.tickets WHERE (status = ? OR status = ?) AND (region = ? OR region IS NULL)",
+			expectArgs:  []any{"open", "new", "eu"},
+		}, {
+			name: "subquery of case",
+			ctx:  context.Background(),
+			builder: builder.Select("id").From("customers").
+				SelectExpr(Case().When(NewExpr("vip"), NewEmptyBuilder().Select("count(*)").From("tickets")).Else(0)),
+			expectErr: ErrPolicyDenied,
+		}, {
+			name: "subquery of aggregate",
+			ctx:  context.Background(),
+			builder: builder.Select("id").From("customers").
+				SelectExpr(CountAll().Filter(Exists(NewEmptyBuilder().Select("1").From("tickets")))),
+			expectErr: ErrPolicyDenied,
+		}, {
+			name: "subquery of and",
+			ctx:  context.Background(),
+			builder: builder.Select("id").From("customers").
+				WhereExpr(AND(NewExpr("vip"), Exists(NewEmptyBuilder().Select("1").From("tickets")))),
+			expectErr: ErrPolicyDenied,
+		}, {
+			name:      "raw join",
+			ctx:       admin,
+			builder:   builder.Select("id").From("customers c").Join(JoinLeft, "tickets t ON t.customer_id = c.id"),
+			expectErr: ErrPolicyDenied,
+		}, {
+			name:        "comma list",
+			ctx:         support,
+			builder:     builder.Select("t.id").From("users u,tickets t"),
+			expectQuery: "SELECT t.id FROM users u,tickets t WHERE (users.active) AND (region = ? OR region IS NULL)",
+			expectArgs:  []any{"eu"},
+		}, {
+			name:      "comma list denied",
+			ctx:       context.Background(),
+			builder:   builder.Select("id").From("customers, tickets"),
+			expectErr: ErrPolicyDenied,
+		}, {
+			name:      "parenthesised raw join",
+			ctx:       admin,
+			builder:   builder.Select("id").From("customers c").JoinRaw("LEFT JOIN (tickets) ON true"),
+			expectErr: ErrPolicyDenied,
+		}, {
+			name: "join expr",
+			ctx:  admin,
+			builder: builder.Select("t.id").From("tickets t").
+				JoinExpr(NewJoinBuilder("t").NewJoin(JoinLeft, users, "u", "id", "user_id")),
+			expectQuery: "SELECT t.id, \"u\".id AS \"u.id\" FROM tickets t " +
+				"LEFT JOIN users as \"u\" ON \"u\".id = \"t\".\"user_id\" AND (users.active)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQLContext(test.ctx)
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
+
+func TestPolicyWithTenant(t *testing.T) {
+	policies := NewPolicyRegistry()
+	policies.Register("users", PolicyFunc(func(ctx context.Context, command string) ([]Expr, error) {
+		return []Expr{NewExpr("u.active")}, nil
+	}))
+	guard := NewTenantGuard()
+	guard.Register(NewTable("users", nil))
+
+	query, args, err := NewEmptyBuilder().Policies(policies).TenantGuard(guard).Tenant(7).
+		Select("t.id").From("tickets t").
+		JoinExpr(NewJoinBuilder("t").NewJoin(JoinLeft, NewTable("users", nil), "u", "id", "user_id")).
+		ToSQLContext(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT t.id FROM tickets t "+
+		"LEFT JOIN users as \"u\" ON \"u\".id = \"t\".\"user_id\" AND \"u\".tenant_id = ? AND (u.active)", query)
+	assert.Equal(t, []any{7}, args)
+}
+
+func TestPolicyExec(t *testing.T) {
+	policies := NewPolicyRegistry()
+	policies.Register("tickets", PolicyFunc(func(ctx context.Context, command string) ([]Expr, error) {
+		return []Expr{NewExpr("region = ?", ctx.Value(roleContextKey{}))}, nil
+	}))
+
+	b, mock := newMockBuilder(t)
+	b = b.Policies(policies)
+
+	mock.ExpectExec("UPDATE tickets SET status = $1 WHERE (id = $2) AND (region = $3)").
+		WithArgs("closed", 1, "eu").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := context.WithValue(context.Background(), roleContextKey{}, "eu")
+	_, err := b.Update().Table("tickets").Set("status", "closed").Where("id = ?", 1).ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,347 @@
+package ondatra
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresReplicationLagQuery returns replication lag of postgres replica in seconds, it is 0 when the replica
+// replayed all received WAL, so a replica of an idle primary is not lagging
+const PostgresReplicationLagQuery = "SELECT CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0 " +
+	"ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) END"
+
+type primaryContextKey struct{}
+
+type sessionContextKey struct{}
+
+// ContextWithPrimary returns context which routes selects to the primary
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// ContextWithSession returns context which remembers writes, selects of the context are routed to the primary
+// during the sticky window of ReaderPool after the last write
+func ContextWithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, &session{})
+}
+
+type session struct {
+	lastWrite atomic.Int64
+}
+
+func sessionFromContext(ctx context.Context) *session {
+	s, _ := ctx.Value(sessionContextKey{}).(*session)
+	return s
+}
+
+// HealthCheckFunc returns error when replica must not receive queries
+type HealthCheckFunc func(ctx context.Context, conn Connection) error
+
+// PingCheck pings replica or selects 1 when connection can not ping
+func PingCheck(ctx context.Context, conn Connection) error {
+	if pinger, ok := conn.(interface {
+		PingContext(ctx context.Context) error
+	}); ok {
+		return pinger.PingContext(ctx)
+	}
+	var one int
+	return conn.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+}
+
+// LagCheck returns health check which ejects replica when lag returned by query in seconds exceeds maxLag
+func LagCheck(query string, maxLag time.Duration) HealthCheckFunc {
+	return func(ctx context.Context, conn Connection) error {
+		var lag float64
+		if err := conn.QueryRowContext(ctx, query).Scan(&lag); err != nil {
+			return err
+		}
+		if lagDuration := time.Duration(lag * float64(time.Second)); lagDuration > maxLag {
+			return fmt.Errorf("%w: %s", ErrReplicaLag, lagDuration)
+		}
+		return nil
+	}
+}
+
+// Replica is a reader connection of ReaderPool
+type Replica struct {
+	conn     Connection
+	healthy  atomic.Bool
+	inFlight atomic.Int64
+	mu       sync.Mutex
+	lastErr  error
+}
+
+func (r *Replica) Healthy() bool {
+	return r.healthy.Load()
+}
+
+// InFlight returns number of running queries, queries returning rows are counted until the first row
+// is received, not until the rows are closed
+func (r *Replica) InFlight() int64 {
+	return r.inFlight.Load()
+}
+
+// Err returns error of the last failed health check
+func (r *Replica) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastErr
+}
+
+// Balancer picks replica for a query from healthy replicas
+type Balancer interface {
+	Pick(replicas []*Replica) *Replica
+}
+
+type roundRobinBalancer struct {
+	next atomic.Uint64
+}
+
+func RoundRobin() Balancer {
+	return &roundRobinBalancer{}
+}
+
+func (b *roundRobinBalancer) Pick(replicas []*Replica) *Replica {
+	return replicas[(b.next.Add(1)-1)%uint64(len(replicas))]
+}
+
+type leastConnectionsBalancer struct{}
+
+// LeastConnections picks replica with the least running queries
+func LeastConnections() Balancer {
+	return leastConnectionsBalancer{}
+}
+
+func (leastConnectionsBalancer) Pick(replicas []*Replica) *Replica {
+	picked := replicas[0]
+	for _, replica := range replicas[1:] {
+		if replica.InFlight() < picked.InFlight() {
+			picked = replica
+		}
+	}
+	return picked
+}
+
+type randomBalancer struct{}
+
+func Random() Balancer {
+	return randomBalancer{}
+}
+
+func (randomBalancer) Pick(replicas []*Replica) *Replica {
+	return replicas[rand.Intn(len(replicas))]
+}
+
+// ReaderPool routes selects to healthy replicas, selects go to the primary when no replica is healthy.
+// Configure it by HealthCheck and StickyWindow before use.
+type ReaderPool struct {
+	replicas     []*Replica
+	balancer     Balancer
+	healthCheck  HealthCheckFunc
+	stickyWindow time.Duration
+}
+
+// NewReaderPool returns pool of readers, all readers are healthy until the first health check
+func NewReaderPool(balancer Balancer, readers ...Connection) *ReaderPool {
+	replicas := make([]*Replica, len(readers))
+	for i := range readers {
+		replicas[i] = &Replica{conn: readers[i]}
+		replicas[i].healthy.Store(true)
+	}
+	return &ReaderPool{
+		replicas:    replicas,
+		balancer:    balancer,
+		healthCheck: PingCheck,
+	}
+}
+
+// HealthCheck set health check of replicas, PingCheck by default
+func (p *ReaderPool) HealthCheck(check HealthCheckFunc) *ReaderPool {
+	p.healthCheck = check
+	return p
+}
+
+// StickyWindow set duration after a write in context of ContextWithSession when selects go to the primary
+func (p *ReaderPool) StickyWindow(window time.Duration) *ReaderPool {
+	p.stickyWindow = window
+	return p
+}
+
+func (p *ReaderPool) Replicas() []*Replica {
+	return p.replicas
+}
+
+// CheckHealth checks all replicas once, failing replicas are ejected until they pass a check
+func (p *ReaderPool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, replica := range p.replicas {
+		wg.Add(1)
+		go func(replica *Replica) {
+			defer wg.Done()
+			err := p.healthCheck(ctx, replica.conn)
+
+			replica.mu.Lock()
+			replica.lastErr = err
+			replica.mu.Unlock()
+			replica.healthy.Store(err == nil)
+		}(replica)
+	}
+	wg.Wait()
+}
+
+// Start checks health of replicas every interval until ctx is done
+func (p *ReaderPool) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+// pick returns connection of healthy replica or nil
+func (p *ReaderPool) pick() Connection {
+	healthy := make([]*Replica, 0, len(p.replicas))
+	for _, replica := range p.replicas {
+		if replica.Healthy() {
+			healthy = append(healthy, replica)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+	return replicaConn{Replica: p.balancer.Pick(healthy)}
+}
+
+func (p *ReaderPool) sticky(ctx context.Context) bool {
+	s := sessionFromContext(ctx)
+	if s == nil || p.stickyWindow <= 0 {
+		return false
+	}
+	lastWrite := s.lastWrite.Load()
+	return lastWrite > 0 && time.Since(time.Unix(0, lastWrite)) < p.stickyWindow
+}
+
+// NewBuilderReaderPool returns builder which routes selects to replicas of the pool
+func NewBuilderReaderPool(writerDB *sqlx.DB, pool *ReaderPool) Builder {
+	return Builder{
+		writerConn: NewDB(writerDB),
+		readerPool: pool,
+		dialect:    DialectByDriver(writerDB.DriverName()),
+	}
+}
+
+// connContext returns connection for the command: writes go to the primary and are remembered by session context,
+// selects go to the primary with ContextWithPrimary or after a recent write, otherwise to a reader
+func (b Builder) connContext(ctx context.Context) Connection {
+	if b.command != CommandSelect {
+		if s := sessionFromContext(ctx); s != nil {
+			s.lastWrite.Store(time.Now().UnixNano())
+		}
+		return b.writerConn
+	}
+
+	if primary, _ := ctx.Value(primaryContextKey{}).(bool); primary {
+		return b.writerConn
+	}
+
+	if b.readerPool != nil {
+		if b.readerPool.sticky(ctx) {
+			return b.writerConn
+		}
+		if conn := b.readerPool.pick(); conn != nil {
+			return conn
+		}
+		return b.writerConn
+	}
+
+	if b.readerConn != nil {
+		return b.readerConn
+	}
+	return b.writerConn
+}
+
+// replicaConn counts running queries of replica
+type replicaConn struct {
+	*Replica
+}
+
+func (c replicaConn) track() func() {
+	c.inFlight.Add(1)
+	return func() {
+		c.inFlight.Add(-1)
+	}
+}
+
+func (c replicaConn) Rebind(query string) string {
+	return c.conn.Rebind(query)
+}
+
+func (c replicaConn) Get(dest any, query string, args ...any) error {
+	defer c.track()()
+	return c.conn.Get(dest, query, args...)
+}
+
+func (c replicaConn) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	defer c.track()()
+	return c.conn.GetContext(ctx, dest, query, args...)
+}
+
+func (c replicaConn) Select(dest any, query string, args ...any) error {
+	defer c.track()()
+	return c.conn.Select(dest, query, args...)
+}
+
+func (c replicaConn) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	defer c.track()()
+	return c.conn.SelectContext(ctx, dest, query, args...)
+}
+
+func (c replicaConn) Exec(query string, args ...any) (sql.Result, error) {
+	defer c.track()()
+	return c.conn.Exec(query, args...)
+}
+
+func (c replicaConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	defer c.track()()
+	return c.conn.ExecContext(ctx, query, args...)
+}
+
+// Query counts the query until rows are returned, reading of the rows is not counted
+func (c replicaConn) Query(query string, args ...any) (*sql.Rows, error) {
+	defer c.track()()
+	return c.conn.Query(query, args...)
+}
+
+// QueryContext counts the query until rows are returned, reading of the rows is not counted
+func (c replicaConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	defer c.track()()
+	return c.conn.QueryContext(ctx, query, args...)
+}
+
+func (c replicaConn) QueryRow(query string, args ...any) *sql.Row {
+	defer c.track()()
+	return c.conn.QueryRow(query, args...)
+}
+
+func (c replicaConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	defer c.track()()
+	return c.conn.QueryRowContext(ctx, query, args...)
+}
+
+func (c replicaConn) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return c.conn.BeginTx(ctx)
+}
@@ -0,0 +1,121 @@
+package ondatra
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConn is a Connection which returns its name as a result of Get
+type fakeConn struct {
+	Connection
+	name    string
+	pingErr error
+}
+
+func (c *fakeConn) Rebind(query string) string {
+	return query
+}
+
+func (c *fakeConn) GetContext(_ context.Context, dest any, _ string, _ ...any) error {
+	*dest.(*string) = c.name
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, _ string, _ ...any) (sql.Result, error) {
+	return sqlmock.NewResult(0, 1), nil
+}
+
+func (c *fakeConn) PingContext(_ context.Context) error {
+	return c.pingErr
+}
+
+func routedTo(t *testing.T, ctx context.Context, b Builder) string {
+	var name string
+	require.NoError(t, b.Select("name").From("users").GetContext(ctx, &name))
+	return name
+}
+
+func TestReaderPool_Balancing(t *testing.T) {
+	primary := &fakeConn{name: "primary"}
+	replica1, replica2 := &fakeConn{name: "replica1"}, &fakeConn{name: "replica2"}
+
+	ctx := context.Background()
+	b := Builder{writerConn: primary, readerPool: NewReaderPool(RoundRobin(), replica1, replica2)}
+	assert.Equal(t, "replica1", routedTo(t, ctx, b))
+	assert.Equal(t, "replica2", routedTo(t, ctx, b))
+	assert.Equal(t, "replica1", routedTo(t, ctx, b))
+
+	pool := NewReaderPool(LeastConnections(), replica1, replica2)
+	pool.Replicas()[0].inFlight.Add(2)
+	b = Builder{writerConn: primary, readerPool: pool}
+	assert.Equal(t, "replica2", routedTo(t, ctx, b))
+	assert.Equal(t, int64(0), pool.Replicas()[1].InFlight())
+
+	b = Builder{writerConn: primary, readerPool: NewReaderPool(Random(), replica1)}
+	assert.Equal(t, "replica1", routedTo(t, ctx, b))
+}
+
+func TestReaderPool_HealthCheck(t *testing.T) {
+	primary := &fakeConn{name: "primary"}
+	replica1, replica2 := &fakeConn{name: "replica1"}, &fakeConn{name: "replica2"}
+	pool := NewReaderPool(RoundRobin(), replica1, replica2)
+	b := Builder{writerConn: primary, readerPool: pool}
+	ctx := context.Background()
+
+	replica1.pingErr = errors.New("connection refused")
+	pool.CheckHealth(ctx)
+	assert.False(t, pool.Replicas()[0].Healthy())
+	assert.EqualError(t, pool.Replicas()[0].Err(), "connection refused")
+	assert.Equal(t, "replica2", routedTo(t, ctx, b))
+	assert.Equal(t, "replica2", routedTo(t, ctx, b))
+
+	replica2.pingErr = errors.New("connection refused")
+	pool.CheckHealth(ctx)
+	assert.Equal(t, "primary", routedTo(t, ctx, b))
+
+	replica1.pingErr = nil
+	pool.CheckHealth(ctx)
+	assert.Equal(t, "replica1", routedTo(t, ctx, b))
+}
+
+func TestReaderPool_Primary(t *testing.T) {
+	primary := &fakeConn{name: "primary"}
+	pool := NewReaderPool(RoundRobin(), &fakeConn{name: "replica"}).StickyWindow(time.Hour)
+	b := Builder{writerConn: primary, readerPool: pool}
+
+	assert.Equal(t, "primary", routedTo(t, ContextWithPrimary(context.Background()), b))
+
+	ctx := ContextWithSession(context.Background())
+	assert.Equal(t, "replica", routedTo(t, ctx, b))
+
+	_, err := b.Update().Table("users").Set("name", "a").ExecContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", routedTo(t, ctx, b))
+	assert.Equal(t, "replica", routedTo(t, context.Background(), b))
+
+	pool.StickyWindow(0)
+	assert.Equal(t, "replica", routedTo(t, ctx, b))
+}
+
+func TestLagCheck(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	conn := NewDB(sqlx.NewDb(db, "postgres"))
+
+	mock.ExpectQuery(PostgresReplicationLagQuery).WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(0.5))
+	mock.ExpectQuery(PostgresReplicationLagQuery).WillReturnRows(sqlmock.NewRows([]string{"lag"}).AddRow(5))
+
+	check := LagCheck(PostgresReplicationLagQuery, time.Second)
+	assert.NoError(t, check(context.Background(), conn))
+	assert.ErrorIs(t, check(context.Background(), conn), ErrReplicaLag)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
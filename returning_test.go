@@ -0,0 +1,138 @@
+package ondatra
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type returningUser struct {
+	ID        int64     `db:"id,column,pk,default"`
+	Name      string    `db:"name,column"`
+	CreatedAt time.Time `db:"created_at,column,default"`
+	UpdatedAt time.Time `db:"updated_at,column,default"`
+}
+
+func newMockBuilder(t *testing.T) (Builder, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		_ = db.Close()
+	})
+	return NewBuilder(sqlx.NewDb(db, "postgres")), mock
+}
+
+func TestBuilder_Returning(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+	}{
+		{
+			name: "insert",
+			builder: NewEmptyBuilder().
+				Insert().
+				Into("users").
+				Columns("name").
+				Values("a").
+				Values("b").
+				Suffix("ON CONFLICT (name) DO NOTHING").
+				Returning("id", "name"),
+			expectQuery: "INSERT INTO users (name) VALUES (?),(?) ON CONFLICT (name) DO NOTHING RETURNING id, name",
+			expectArgs:  []any{"a", "b"},
+		}, {
+			name: "insert struct slice",
+			builder: NewEmptyBuilder().
+				Insert().
+				Into("users").
+				StructColumns(&[]returningUser{{Name: "a"}, {Name: "b", CreatedAt: createdAt}}),
+			expectQuery: "INSERT INTO users (name, created_at) VALUES (?,DEFAULT),(?,?) RETURNING id, created_at, updated_at",
+			expectArgs:  []any{"a", "b", createdAt},
+		}, {
+			name: "update struct",
+			builder: NewEmptyBuilder().
+				Update().
+				Table("users").
+				StructColumns(&returningUser{ID: 1, Name: "a"}),
+			expectQuery: "UPDATE users SET name = ?, updated_at = DEFAULT WHERE id = ? RETURNING updated_at",
+			expectArgs:  []any{"a", int64(1)},
+		}, {
+			name: "delete",
+			builder: NewEmptyBuilder().
+				Delete().
+				From("users").
+				Where("id = ?", 1).
+				ReturningAll(),
+			expectQuery: "DELETE FROM users WHERE id = ? RETURNING *",
+			expectArgs:  []any{1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
+
+func TestBuilder_ExecReturning(t *testing.T) {
+	b, mock := newMockBuilder(t)
+	createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	users := []returningUser{{Name: "a"}, {Name: "b"}}
+	mock.ExpectQuery("INSERT INTO users (name) VALUES ($1),($2) RETURNING id, created_at, updated_at").
+		WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
+			AddRow(1, createdAt, createdAt).
+			AddRow(2, createdAt, createdAt))
+
+	err := b.Insert().Into("users").StructColumns(&users).ExecReturningContext(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []returningUser{
+		{ID: 1, Name: "a", CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: 2, Name: "b", CreatedAt: createdAt, UpdatedAt: createdAt},
+	}, users)
+}
+
+func TestBuilder_ExecReturningInto(t *testing.T) {
+	b, mock := newMockBuilder(t)
+	createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("DELETE FROM users WHERE name = $1 RETURNING *").
+		WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+			AddRow(1, "a", createdAt, createdAt).
+			AddRow(2, "a", createdAt, createdAt))
+
+	var deleted []returningUser
+	err := b.Delete().From("users").Where("name = ?", "a").ReturningAll().ExecReturningInto(context.Background(), &deleted)
+	assert.NoError(t, err)
+	assert.Equal(t, []returningUser{
+		{ID: 1, Name: "a", CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: 2, Name: "a", CreatedAt: createdAt, UpdatedAt: createdAt},
+	}, deleted)
+
+	mock.ExpectQuery("UPDATE users SET name = $1 WHERE id = $2 RETURNING id, name").
+		WithArgs("b", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "b"))
+
+	var updated returningUser
+	err = b.Update().Table("users").Set("name", "b").Where("id = ?", 1).Returning("id", "name").
+		ExecReturningInto(context.Background(), &updated)
+	assert.NoError(t, err)
+	assert.Equal(t, returningUser{ID: 1, Name: "b"}, updated)
+
+	err = b.Update().Table("users").Set("name", "b").ExecReturningInto(context.Background(), &updated)
+	assert.ErrorIs(t, err, ErrReturningNotSet)
+}
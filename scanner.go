@@ -0,0 +1,196 @@
+package ondatra
+
+import "strings"
+
+const (
+	placeholderPositional = iota // ?
+	placeholderEscaped           // ?? => ?
+	placeholderNamed             // :name or @name
+	placeholderNumbered          // $1 or :1
+)
+
+// placeholder is a parameter marker found in a raw SQL string.
+// The sql[start:end] slice is the marker itself, name is set for named markers.
+type placeholder struct {
+	kind  int
+	start int
+	end   int
+	name  string
+}
+
+// scanPlaceholders returns all parameter markers of the sql in order of appearance.
+// String literals, quoted identifiers, comments and dollar-quoted strings are skipped,
+// as well as postgres "::" casts, "?|", "?&" json operators and colons of array slices, e.g. arr[1:2].
+func scanPlaceholders(sql string) []placeholder {
+	var placeholders []placeholder
+	var brackets int
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '[':
+			brackets++
+			i++
+		case c == ']':
+			brackets = max(0, brackets-1)
+			i++
+		case c == ':' && (brackets > 0 || i > 0 && isDigit(sql[i-1])): // array slice bounds
+			i++
+		case c == '\'':
+			i = skipQuoted(sql, i, '\'', isEscapeString(sql, i))
+		case c == '"' || c == '`':
+			i = skipQuoted(sql, i, c, false)
+		case c == '-' && next(sql, i) == '-':
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(sql)
+			}
+		case c == '/' && next(sql, i) == '*':
+			i = skipBlockComment(sql, i)
+		case c == '$' && isDigit(next(sql, i)):
+			end := skipDigits(sql, i+1)
+			placeholders = append(placeholders, placeholder{kind: placeholderNumbered, start: i, end: end, name: sql[i+1 : end]})
+			i = end
+		case c == '$':
+			i = skipDollarQuoted(sql, i)
+		case c == '?':
+			switch next(sql, i) {
+			case '?':
+				placeholders = append(placeholders, placeholder{kind: placeholderEscaped, start: i, end: i + 2})
+				i += 2
+			case '&':
+				i += 2
+			case '|':
+				if next(sql, i+1) == '|' { // placeholder followed by || concatenation
+					placeholders = append(placeholders, placeholder{kind: placeholderPositional, start: i, end: i + 1})
+					i++
+				} else {
+					i += 2
+				}
+			default:
+				placeholders = append(placeholders, placeholder{kind: placeholderPositional, start: i, end: i + 1})
+				i++
+			}
+		case c == ':' || c == '@':
+			if next(sql, i) == c { // :: cast or @@ system variable
+				i = skipIdentifier(sql, i+2)
+				continue
+			}
+			if c == ':' && isDigit(next(sql, i)) {
+				end := skipDigits(sql, i+1)
+				placeholders = append(placeholders, placeholder{kind: placeholderNumbered, start: i, end: end, name: sql[i+1 : end]})
+				i = end
+				continue
+			}
+			end := skipIdentifier(sql, i+1)
+			if end > i+1 && isIdentifierStart(sql[i+1]) {
+				placeholders = append(placeholders, placeholder{kind: placeholderNamed, start: i, end: end, name: sql[i+1 : end]})
+			}
+			i = max(end, i+1)
+		case isIdentifierStart(c):
+			i = skipIdentifier(sql, i)
+		default:
+			i++
+		}
+	}
+	return placeholders
+}
+
+func next(sql string, i int) byte {
+	if i+1 < len(sql) {
+		return sql[i+1]
+	}
+	return 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func skipDigits(sql string, i int) int {
+	for i < len(sql) && isDigit(sql[i]) {
+		i++
+	}
+	return i
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || isDigit(c) || c == '$'
+}
+
+func skipIdentifier(sql string, i int) int {
+	for i < len(sql) && isIdentifierChar(sql[i]) {
+		i++
+	}
+	return i
+}
+
+// isEscapeString reports whether the literal at position i is a postgres E'...' string
+func isEscapeString(sql string, i int) bool {
+	if i == 0 || sql[i-1] != 'E' && sql[i-1] != 'e' {
+		return false
+	}
+	return i == 1 || !isIdentifierChar(sql[i-2])
+}
+
+// skipQuoted skips the literal or identifier started at position i, doubled quote is an escaped quote
+func skipQuoted(sql string, i int, quote byte, backslash bool) int {
+	for i++; i < len(sql); i++ {
+		switch sql[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if next(sql, i) != quote {
+				return i + 1
+			}
+			i++
+		}
+	}
+	return len(sql)
+}
+
+// skipBlockComment skips the comment started at position i, postgres allows nested comments
+func skipBlockComment(sql string, i int) int {
+	depth := 0
+	for i < len(sql) {
+		switch {
+		case sql[i] == '/' && next(sql, i) == '*':
+			depth++
+			i += 2
+		case sql[i] == '*' && next(sql, i) == '/':
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return len(sql)
+}
+
+// skipDollarQuoted skips $tag$...$tag$ string started at position i, $1 style parameters are left as is
+func skipDollarQuoted(sql string, i int) int {
+	end := i + 1
+	if end < len(sql) && isIdentifierStart(sql[end]) {
+		for end < len(sql) && isIdentifierChar(sql[end]) && sql[end] != '$' {
+			end++
+		}
+	}
+	if end >= len(sql) || sql[end] != '$' {
+		return end
+	}
+
+	tag := sql[i : end+1]
+	if closing := strings.Index(sql[end+1:], tag); closing >= 0 {
+		return end + 1 + closing + len(tag)
+	}
+	return len(sql)
+}
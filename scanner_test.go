@@ -0,0 +1,66 @@
+package ondatra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScanPlaceholders(t *testing.T) {
+	var tests = []struct {
+		name   string
+		sql    string
+		expect []string
+	}{
+		{
+			name:   "positional",
+			sql:    "a = ? AND b = ?",
+			expect: []string{"?", "?"},
+		}, {
+			name:   "escaped",
+			sql:    "a ?? 'key' AND b = ?",
+			expect: []string{"??", "?"},
+		}, {
+			name:   "string literals",
+			sql:    "a = 'it''s?' AND b = E'\\'?' AND c = ?",
+			expect: []string{"?"},
+		}, {
+			name:   "quoted identifiers",
+			sql:    "\"a?\" = ? AND `b?` = ?",
+			expect: []string{"?", "?"},
+		}, {
+			name:   "comments",
+			sql:    "a = ? -- b = ?\nAND /* c = ? /* nested ? */ d = ? */ e = ?",
+			expect: []string{"?", "?"},
+		}, {
+			name:   "dollar quoted",
+			sql:    "a = $$?$$ AND b = $tag$ $$ ? $tag$ AND c = $1 AND d = ?",
+			expect: []string{"$1", "?"},
+		}, {
+			name:   "json operators",
+			sql:    "a ?| b AND a ?& c AND d = ?||'x'",
+			expect: []string{"?"},
+		}, {
+			name:   "numbered",
+			sql:    "a = $1 AND b = :2 AND c = @p3",
+			expect: []string{"$1", ":2", "@p3"},
+		}, {
+			name:   "array slices",
+			sql:    "a = arr[1:2] AND b = arr[:n] AND c = arr[?:?][1] AND d = :1",
+			expect: []string{"?", "?", ":1"},
+		}, {
+			name:   "named",
+			sql:    "a = :a AND b::text = @b AND c = @@version AND d = ':d' AND e = :e_1",
+			expect: []string{":a", "@b", ":e_1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var markers []string
+			for _, p := range scanPlaceholders(test.sql) {
+				markers = append(markers, test.sql[p.start:p.end])
+			}
+			assert.Equal(t, test.expect, markers)
+		})
+	}
+}
@@ -0,0 +1,445 @@
+package ondatra
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	postgresSchemaTablesQuery = `SELECT table_name FROM information_schema.tables
+WHERE table_schema = COALESCE(NULLIF(?, ''), current_schema()) AND table_type = 'BASE TABLE'`
+
+	postgresSchemaColumnsQuery = `SELECT table_name, column_name,
+CASE WHEN data_type IN ('ARRAY', 'USER-DEFINED') THEN udt_name ELSE data_type END,
+is_nullable = 'YES', column_default, ordinal_position
+FROM information_schema.columns
+WHERE table_schema = COALESCE(NULLIF(?, ''), current_schema())
+ORDER BY table_name, ordinal_position`
+
+	postgresSchemaKeysQuery = `SELECT tc.table_name, tc.constraint_name, tc.constraint_type, kcu.column_name,
+COALESCE(ref.table_name, ''), COALESCE(ref.column_name, '')
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu ON kcu.constraint_schema = tc.constraint_schema
+AND kcu.constraint_name = tc.constraint_name AND kcu.table_name = tc.table_name
+LEFT JOIN information_schema.referential_constraints rc ON rc.constraint_schema = tc.constraint_schema
+AND rc.constraint_name = tc.constraint_name
+LEFT JOIN information_schema.key_column_usage ref ON ref.constraint_schema = rc.unique_constraint_schema
+AND ref.constraint_name = rc.unique_constraint_name AND ref.ordinal_position = kcu.position_in_unique_constraint
+WHERE tc.table_schema = COALESCE(NULLIF(?, ''), current_schema()) AND tc.constraint_type IN ('PRIMARY KEY', 'FOREIGN KEY')
+ORDER BY tc.table_name, tc.constraint_name, kcu.ordinal_position`
+
+	postgresSchemaIndexesQuery = `SELECT t.relname, i.relname, ix.indisunique, a.attname
+FROM pg_index ix
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE n.nspname = COALESCE(NULLIF(?, ''), current_schema()) AND NOT ix.indisprimary
+ORDER BY t.relname, i.relname, k.ord`
+
+	mysqlSchemaTablesQuery = `SELECT table_name FROM information_schema.tables
+WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND table_type = 'BASE TABLE'`
+
+	mysqlSchemaColumnsQuery = `SELECT table_name, column_name, column_type, is_nullable = 'YES', column_default, ordinal_position
+FROM information_schema.columns
+WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE())
+ORDER BY table_name, ordinal_position`
+
+	mysqlSchemaKeysQuery = `SELECT kcu.table_name, kcu.constraint_name, tc.constraint_type, kcu.column_name,
+COALESCE(kcu.referenced_table_name, ''), COALESCE(kcu.referenced_column_name, '')
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu ON kcu.constraint_schema = tc.constraint_schema
+AND kcu.constraint_name = tc.constraint_name AND kcu.table_name = tc.table_name
+WHERE tc.table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND tc.constraint_type IN ('PRIMARY KEY', 'FOREIGN KEY')
+ORDER BY kcu.table_name, kcu.constraint_name, kcu.ordinal_position`
+
+	mysqlSchemaIndexesQuery = `SELECT table_name, index_name, non_unique = 0, COALESCE(column_name, '')
+FROM information_schema.statistics
+WHERE table_schema = COALESCE(NULLIF(?, ''), DATABASE()) AND index_name <> 'PRIMARY'
+ORDER BY table_name, index_name, seq_in_index`
+
+	sqliteSchemaTablesQuery = `SELECT name FROM %s.sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%%'`
+
+	sqliteSchemaColumnsQuery = `SELECT name, type, "notnull" = 0, dflt_value, cid + 1, pk FROM pragma_table_info(?, ?) ORDER BY cid`
+
+	sqliteSchemaForeignKeysQuery = `SELECT id, "table", "from", "to" FROM pragma_foreign_key_list(?, ?) ORDER BY id, seq`
+
+	sqliteSchemaIndexesQuery = `SELECT name, "unique" FROM pragma_index_list(?, ?) WHERE origin <> 'pk' ORDER BY name`
+
+	sqliteSchemaIndexColumnsQuery = `SELECT COALESCE(name, '') FROM pragma_index_info(?, ?) ORDER BY seqno`
+)
+
+// Schema is a database schema returned by Introspect
+type Schema struct {
+	Tables []TableSchema
+}
+
+// TableSchema describes table of the database
+type TableSchema struct {
+	Name        string
+	Columns     []ColumnSchema
+	PrimaryKey  []string
+	ForeignKeys []ForeignKey
+	Indexes     []Index
+}
+
+// ColumnSchema describes column, Type is the type of the database, e.g. character varying or int(11) unsigned
+type ColumnSchema struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	Default    sql.NullString
+	PrimaryKey bool
+	Position   int
+}
+
+// ForeignKey describes foreign key, Columns reference RefColumns of RefTable in the same order
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// Index describes secondary index, indexes of primary keys are not included
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// SchemaError is returned by Schema.Validate for a table or column which is not in the database
+type SchemaError struct {
+	Table  string
+	Column string
+	Reason string
+}
+
+func (e SchemaError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("%s: table %q: %s", ErrSchemaMismatch, e.Table, e.Reason)
+	}
+	return fmt.Sprintf("%s: column %q of table %q: %s", ErrSchemaMismatch, e.Column, e.Table, e.Reason)
+}
+
+func (e SchemaError) Unwrap() error {
+	return ErrSchemaMismatch
+}
+
+// Table returns schema of the table by name
+func (s Schema) Table(name string) (TableSchema, bool) {
+	for _, table := range s.Tables {
+		if table.Name == name {
+			return table, true
+		}
+	}
+	return TableSchema{}, false
+}
+
+// Validate checks that tables and their columns exist in the schema, it returns SchemaError of every mismatch
+func (s Schema) Validate(tables ...Table) error {
+	var errs []error
+	for _, table := range tables {
+		tableSchema, ok := s.Table(table.name)
+		if !ok {
+			errs = append(errs, SchemaError{Table: table.name, Reason: "table does not exist"})
+			continue
+		}
+		for _, column := range table.columns {
+			if _, ok := tableSchema.Column(column); !ok {
+				errs = append(errs, SchemaError{Table: table.name, Column: column, Reason: "column does not exist"})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Column returns schema of the column by name
+func (t TableSchema) Column(name string) (ColumnSchema, bool) {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return ColumnSchema{}, false
+}
+
+// Table returns Table with columns of the schema, it can be used by Strict
+func (t TableSchema) Table() Table {
+	columns := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		columns[i] = column.Name
+	}
+	return NewTable(t.Name, columns)
+}
+
+// Introspect returns schema of tables of the current postgres schema, mysql database or main sqlite database,
+// all tables are returned when tables are not set
+func (b Builder) Introspect(ctx context.Context, tables ...string) (Schema, error) {
+	return b.IntrospectSchema(ctx, "", tables...)
+}
+
+// IntrospectSchema returns schema of tables of postgres schema, mysql database or attached sqlite database
+func (b Builder) IntrospectSchema(ctx context.Context, schema string, tables ...string) (Schema, error) {
+	conn := b.writerConn
+	if conn == nil {
+		conn = b.readerConn
+	}
+	if conn == nil {
+		return Schema{}, SqlDBNotSet
+	}
+
+	introspector := schemaIntrospector{conn: conn, schema: schema, tables: make(map[string]*TableSchema)}
+	var err error
+	switch b.dialect {
+	case DialectPostgres:
+		err = introspector.informationSchema(ctx, postgresSchemaTablesQuery, postgresSchemaColumnsQuery,
+			postgresSchemaKeysQuery, postgresSchemaIndexesQuery)
+	case DialectMySQL:
+		err = introspector.informationSchema(ctx, mysqlSchemaTablesQuery, mysqlSchemaColumnsQuery,
+			mysqlSchemaKeysQuery, mysqlSchemaIndexesQuery)
+	case DialectSQLite:
+		err = introspector.sqlite(ctx)
+	default:
+		err = fmt.Errorf("%w: %q", ErrDialectNotSupported, b.dialect)
+	}
+	if err != nil {
+		return Schema{}, err
+	}
+
+	return introspector.result(tables), nil
+}
+
+type schemaIntrospector struct {
+	conn   Connection
+	schema string
+	tables map[string]*TableSchema
+}
+
+func (s *schemaIntrospector) result(names []string) Schema {
+	var schema Schema
+	for name, table := range s.tables {
+		if len(names) == 0 || slices.Contains(names, name) {
+			schema.Tables = append(schema.Tables, *table)
+		}
+	}
+	sort.Slice(schema.Tables, func(i, j int) bool {
+		return schema.Tables[i].Name < schema.Tables[j].Name
+	})
+	return schema
+}
+
+// query scans every row of the query into dest
+func (s *schemaIntrospector) query(ctx context.Context, query string, args []any, dest []any, row func() error) error {
+	rows, err := s.conn.QueryContext(ctx, s.conn.Rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+		if err = row(); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+func (s *schemaIntrospector) informationSchema(ctx context.Context, tablesQuery, columnsQuery, keysQuery, indexesQuery string) error {
+	args := []any{s.schema}
+
+	var tableName string
+	err := s.query(ctx, tablesQuery, args, []any{&tableName}, func() error {
+		s.tables[tableName] = &TableSchema{Name: tableName}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var column ColumnSchema
+	err = s.query(ctx, columnsQuery, args,
+		[]any{&tableName, &column.Name, &column.Type, &column.Nullable, &column.Default, &column.Position},
+		func() error {
+			if table, ok := s.tables[tableName]; ok {
+				table.Columns = append(table.Columns, column)
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	var constraintName, constraintType, columnName, refTable, refColumn string
+	err = s.query(ctx, keysQuery, args,
+		[]any{&tableName, &constraintName, &constraintType, &columnName, &refTable, &refColumn},
+		func() error {
+			table, ok := s.tables[tableName]
+			if !ok {
+				return nil
+			}
+			if constraintType == "PRIMARY KEY" {
+				table.addPrimaryKey(columnName)
+				return nil
+			}
+			table.addForeignKey(constraintName, columnName, refTable, refColumn)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	var indexName string
+	var unique bool
+	return s.query(ctx, indexesQuery, args, []any{&tableName, &indexName, &unique, &columnName}, func() error {
+		if table, ok := s.tables[tableName]; ok {
+			table.addIndexColumn(indexName, unique, columnName)
+		}
+		return nil
+	})
+}
+
+func (s *schemaIntrospector) sqlite(ctx context.Context) error {
+	schema := s.schema
+	if schema == "" {
+		schema = "main"
+	}
+	if err := validateIdent(schema); err != nil {
+		return err
+	}
+
+	var tableName string
+	tablesQuery := fmt.Sprintf(sqliteSchemaTablesQuery, DialectSQLite.QuoteIdent(schema))
+	err := s.query(ctx, tablesQuery, nil, []any{&tableName}, func() error {
+		s.tables[tableName] = &TableSchema{Name: tableName}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, table := range s.tables {
+		if err = s.sqliteTable(ctx, schema, table); err != nil {
+			return fmt.Errorf("table %s: %w", table.Name, err)
+		}
+	}
+
+	// foreign keys without columns reference primary key of the table
+	for _, table := range s.tables {
+		for i, foreignKey := range table.ForeignKeys {
+			if ref, ok := s.tables[foreignKey.RefTable]; ok && slices.Contains(foreignKey.RefColumns, "") {
+				table.ForeignKeys[i].RefColumns = slices.Clone(ref.PrimaryKey)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *schemaIntrospector) sqliteTable(ctx context.Context, schema string, table *TableSchema) error {
+	args := []any{table.Name, schema}
+
+	var column ColumnSchema
+	var primaryKeys []ColumnSchema
+	var pk int
+	err := s.query(ctx, sqliteSchemaColumnsQuery, args,
+		[]any{&column.Name, &column.Type, &column.Nullable, &column.Default, &column.Position, &pk},
+		func() error {
+			table.Columns = append(table.Columns, column)
+			if pk > 0 {
+				primaryKeys = append(primaryKeys, ColumnSchema{Name: column.Name, Position: pk})
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	sort.Slice(primaryKeys, func(i, j int) bool {
+		return primaryKeys[i].Position < primaryKeys[j].Position
+	})
+	for _, primaryKey := range primaryKeys {
+		table.addPrimaryKey(primaryKey.Name)
+	}
+
+	var id int
+	var refTable, columnName string
+	var refColumn sql.NullString
+	err = s.query(ctx, sqliteSchemaForeignKeysQuery, args, []any{&id, &refTable, &columnName, &refColumn}, func() error {
+		table.addForeignKey(fmt.Sprintf("%s_fkey%d", table.Name, id), columnName, refTable, refColumn.String)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, foreignKey := range table.ForeignKeys {
+		table.ForeignKeys[i].Name = fmt.Sprintf("%s_%s_fkey", table.Name, strings.Join(foreignKey.Columns, "_"))
+	}
+
+	var indexes []Index
+	var index Index
+	err = s.query(ctx, sqliteSchemaIndexesQuery, args, []any{&index.Name, &index.Unique}, func() error {
+		indexes = append(indexes, index)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, ix := range indexes {
+		err = s.query(ctx, sqliteSchemaIndexColumnsQuery, []any{ix.Name, schema}, []any{&columnName}, func() error {
+			table.addIndexColumn(ix.Name, ix.Unique, columnName)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TableSchema) addPrimaryKey(column string) {
+	t.PrimaryKey = append(t.PrimaryKey, column)
+	for i := range t.Columns {
+		if t.Columns[i].Name == column {
+			t.Columns[i].PrimaryKey = true
+		}
+	}
+}
+
+// addForeignKey adds column to the foreign key of the constraint name, the last foreign key has the same name
+// because rows are ordered by constraint
+func (t *TableSchema) addForeignKey(name, column, refTable, refColumn string) {
+	if n := len(t.ForeignKeys); n > 0 && t.ForeignKeys[n-1].Name == name {
+		t.ForeignKeys[n-1].Columns = append(t.ForeignKeys[n-1].Columns, column)
+		t.ForeignKeys[n-1].RefColumns = append(t.ForeignKeys[n-1].RefColumns, refColumn)
+		return
+	}
+	t.ForeignKeys = append(t.ForeignKeys, ForeignKey{
+		Name:       name,
+		Columns:    []string{column},
+		RefTable:   refTable,
+		RefColumns: []string{refColumn},
+	})
+}
+
+// addIndexColumn adds column to the index of the name, expression columns are skipped
+func (t *TableSchema) addIndexColumn(name string, unique bool, column string) {
+	n := len(t.Indexes)
+	if n == 0 || t.Indexes[n-1].Name != name {
+		t.Indexes = append(t.Indexes, Index{Name: name, Unique: unique})
+		n++
+	}
+	if column != "" {
+		t.Indexes[n-1].Columns = append(t.Indexes[n-1].Columns, column)
+	}
+}
@@ -0,0 +1,149 @@
+package ondatra
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteBuilder(t *testing.T, statements ...string) Builder {
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	for _, statement := range statements {
+		_, err = db.Exec(statement)
+		require.NoError(t, err)
+	}
+	return NewBuilder(db)
+}
+
+func TestBuilder_Introspect(t *testing.T) {
+	b := newSQLiteBuilder(t,
+		`CREATE TABLE users (
+			id INTEGER PRIMARY KEY,
+			email TEXT NOT NULL UNIQUE,
+			name TEXT,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE orders (
+			id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL REFERENCES users,
+			number TEXT NOT NULL,
+			amount DECIMAL(10, 2),
+			PRIMARY KEY (id, number)
+		)`,
+		`CREATE INDEX orders_user_number_idx ON orders (user_id, number)`,
+	)
+
+	schema, err := b.Introspect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, schema.Tables, 2)
+
+	orders, ok := schema.Table("orders")
+	require.True(t, ok)
+	assert.Equal(t, TableSchema{
+		Name: "orders",
+		Columns: []ColumnSchema{
+			{Name: "id", Type: "INTEGER", PrimaryKey: true, Position: 1},
+			{Name: "user_id", Type: "INTEGER", Position: 2},
+			{Name: "number", Type: "TEXT", PrimaryKey: true, Position: 3},
+			{Name: "amount", Type: "DECIMAL(10, 2)", Nullable: true, Position: 4},
+		},
+		PrimaryKey: []string{"id", "number"},
+		ForeignKeys: []ForeignKey{
+			{Name: "orders_user_id_fkey", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+		},
+		Indexes: []Index{{Name: "orders_user_number_idx", Columns: []string{"user_id", "number"}}},
+	}, orders)
+
+	users, ok := schema.Table("users")
+	require.True(t, ok)
+	assert.Equal(t, []string{"id"}, users.PrimaryKey)
+	assert.Equal(t, sql.NullString{String: "CURRENT_TIMESTAMP", Valid: true}, users.Columns[3].Default)
+	assert.True(t, users.Columns[2].Nullable)
+	require.Len(t, users.Indexes, 1)
+	assert.True(t, users.Indexes[0].Unique)
+	assert.Equal(t, []string{"email"}, users.Indexes[0].Columns)
+	assert.Equal(t, NewTable("users", []string{"id", "email", "name", "created_at"}), users.Table())
+
+	schema, err = b.Introspect(context.Background(), "users")
+	require.NoError(t, err)
+	assert.Len(t, schema.Tables, 1)
+
+	_, err = NewEmptyBuilder().Introspect(context.Background())
+	assert.ErrorIs(t, err, SqlDBNotSet)
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema := Schema{Tables: []TableSchema{{
+		Name:    "users",
+		Columns: []ColumnSchema{{Name: "id"}, {Name: "name"}},
+	}}}
+
+	assert.NoError(t, schema.Validate(NewTable("users", []string{"id", "name"})))
+
+	err := schema.Validate(NewTable("users", []string{"id", "email"}), NewTable("orders", nil))
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+	assert.EqualError(t, err, "schema mismatch: column \"email\" of table \"users\": column does not exist\n"+
+		"schema mismatch: table \"orders\": table does not exist")
+}
+
+func TestBuilder_IntrospectPostgres(t *testing.T) {
+	b, mock := newMockBuilder(t)
+	rebind := b.writerConn.Rebind
+
+	mock.ExpectQuery(rebind(postgresSchemaTablesQuery)).WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name"}).AddRow("users").AddRow("orders"))
+	mock.ExpectQuery(rebind(postgresSchemaColumnsQuery)).WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "column_name", "data_type", "nullable", "default", "position"}).
+			AddRow("orders", "id", "bigint", false, "nextval('orders_id_seq'::regclass)", 1).
+			AddRow("orders", "user_id", "bigint", false, nil, 2).
+			AddRow("users", "id", "bigint", false, nil, 1).
+			AddRow("users", "tags", "_text", true, nil, 2))
+	mock.ExpectQuery(rebind(postgresSchemaKeysQuery)).WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "name", "type", "column", "ref_table", "ref_column"}).
+			AddRow("orders", "orders_pkey", "PRIMARY KEY", "id", "", "").
+			AddRow("orders", "orders_user_id_fkey", "FOREIGN KEY", "user_id", "users", "id").
+			AddRow("users", "users_pkey", "PRIMARY KEY", "id", "", ""))
+	mock.ExpectQuery(rebind(postgresSchemaIndexesQuery)).WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "index_name", "unique", "column"}).
+			AddRow("orders", "orders_user_id_idx", false, "user_id"))
+
+	schema, err := b.IntrospectSchema(context.Background(), "public")
+	require.NoError(t, err)
+	assert.Equal(t, Schema{Tables: []TableSchema{
+		{
+			Name: "orders",
+			Columns: []ColumnSchema{
+				{
+					Name:       "id",
+					Type:       "bigint",
+					Default:    sql.NullString{String: "nextval('orders_id_seq'::regclass)", Valid: true},
+					PrimaryKey: true,
+					Position:   1,
+				},
+				{Name: "user_id", Type: "bigint", Position: 2},
+			},
+			PrimaryKey: []string{"id"},
+			ForeignKeys: []ForeignKey{
+				{Name: "orders_user_id_fkey", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+			},
+			Indexes: []Index{{Name: "orders_user_id_idx", Columns: []string{"user_id"}}},
+		}, {
+			Name: "users",
+			Columns: []ColumnSchema{
+				{Name: "id", Type: "bigint", PrimaryKey: true, Position: 1},
+				{Name: "tags", Type: "_text", Nullable: true, Position: 2},
+			},
+			PrimaryKey: []string{"id"},
+		},
+	}}, schema)
+}
@@ -0,0 +1,89 @@
+package ondatra
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ScopeClause is a named group of clauses, e.g. "active" for WHERE deleted_at IS NULL
+type ScopeClause struct {
+	name    string
+	clauses Clauses
+}
+
+func Scope(name string, clauses ...Clause) ScopeClause {
+	return ScopeClause{
+		name:    name,
+		clauses: clauses,
+	}
+}
+
+func (s ScopeClause) Name() string {
+	return s.name
+}
+
+func (s ScopeClause) Apply(b Builder) Builder {
+	return s.clauses.Apply(b)
+}
+
+// ScopeRegistry keeps named scopes per table, so scopes can be applied by names from API query params
+type ScopeRegistry struct {
+	mu     sync.RWMutex
+	scopes map[string]map[string]ScopeClause
+}
+
+func NewScopeRegistry() *ScopeRegistry {
+	return &ScopeRegistry{
+		scopes: make(map[string]map[string]ScopeClause),
+	}
+}
+
+// Register add scopes of the table, scope with the same name is replaced
+func (r *ScopeRegistry) Register(table string, scopes ...ScopeClause) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.scopes[table] == nil {
+		r.scopes[table] = make(map[string]ScopeClause)
+	}
+	for _, scope := range scopes {
+		r.scopes[table][scope.name] = scope
+	}
+}
+
+func (r *ScopeRegistry) Scope(table, name string) (ScopeClause, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scope, ok := r.scopes[table][name]
+	return scope, ok
+}
+
+// Scopes returns clause of scopes by names, unknown names are rejected
+func (r *ScopeRegistry) Scopes(table string, names ...string) (Clause, error) {
+	clauses := make(Clauses, 0, len(names))
+	for _, name := range names {
+		scope, ok := r.Scope(table, name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrScopeNotFound, name)
+		}
+		clauses = append(clauses, scope)
+	}
+	return clauses, nil
+}
+
+// FromQuery returns clause of scopes named in the query param, e.g. ?scope=active,recent or ?scope=active&scope=recent
+// Filter of the same query params must ignore the param by Filter.IgnoreParams.
+func (r *ScopeRegistry) FromQuery(table string, values url.Values, param string) (Clause, error) {
+	var names []string
+	for _, value := range values[param] {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return r.Scopes(table, names...)
+}
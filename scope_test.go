@@ -0,0 +1,68 @@
+package ondatra
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeRegistry(t *testing.T) {
+	registry := NewScopeRegistry()
+	registry.Register("users",
+		Scope("active", Where("users.deleted_at IS NULL")),
+		Scope("recent", OrderBy("users.created_at DESC"), Limit(10)),
+	)
+	registry.Register("orders", Scope("paid", Where("orders.status = ?", "paid")))
+
+	var tests = []struct {
+		name        string
+		table       string
+		query       string
+		expectQuery string
+		expectArgs  []any
+		expectErr   error
+	}{
+		{
+			name:        "no scopes",
+			table:       "users",
+			query:       "",
+			expectQuery: "SELECT id FROM users",
+		}, {
+			name:        "comma separated",
+			table:       "users",
+			query:       "scope=active,recent",
+			expectQuery: "SELECT id FROM users WHERE users.deleted_at IS NULL ORDER BY users.created_at DESC LIMIT 10",
+		}, {
+			name:        "repeated param",
+			table:       "orders",
+			query:       "scope=paid&scope=",
+			expectQuery: "SELECT id FROM orders WHERE orders.status = ?",
+			expectArgs:  []any{"paid"},
+		}, {
+			name:      "scope of other table",
+			table:     "users",
+			query:     "scope=paid",
+			expectErr: ErrScopeNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := url.ParseQuery(test.query)
+			assert.NoError(t, err)
+
+			clause, err := registry.FromQuery(test.table, values, "scope")
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			query, args, err := NewEmptyBuilder().Select("id").From(test.table).Clauses(clause).ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
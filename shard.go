@@ -0,0 +1,185 @@
+package ondatra
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+type shardContextKey struct{}
+
+// ContextWithShardKey returns context with shard key used by ShardedBuilder.ForContext
+func ContextWithShardKey(ctx context.Context, key any) context.Context {
+	return context.WithValue(ctx, shardContextKey{}, key)
+}
+
+// ShardFunc returns index of shard for the key
+type ShardFunc func(key any) (int, error)
+
+// HashShard spreads keys over n shards by FNV hash of the key
+func HashShard(n int) ShardFunc {
+	return func(key any) (int, error) {
+		if n <= 0 {
+			return 0, fmt.Errorf("%w: no shards", ErrShardNotFound)
+		}
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(fmt.Sprint(key)))
+		return int(h.Sum32() % uint32(n)), nil
+	}
+}
+
+// RangeShard returns shard i for integer key less than upperBounds[i], greater keys go to the last shard len(upperBounds)
+func RangeShard(upperBounds ...int64) ShardFunc {
+	return func(key any) (int, error) {
+		v := reflect.ValueOf(key)
+		var k int64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			k = v.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			k = int64(v.Uint())
+		default:
+			return 0, fmt.Errorf("%w: key %v is not integer", ErrShardNotFound, key)
+		}
+		return sort.Search(len(upperBounds), func(i int) bool { return k < upperBounds[i] }), nil
+	}
+}
+
+// LookupShard returns shard of the key from the map, keys are formatted by fmt.Sprint
+func LookupShard(shards map[string]int) ShardFunc {
+	return func(key any) (int, error) {
+		shard, ok := shards[fmt.Sprint(key)]
+		if !ok {
+			return 0, fmt.Errorf("%w: key %v", ErrShardNotFound, key)
+		}
+		return shard, nil
+	}
+}
+
+// ShardedBuilder routes statements to shards by shard key, every shard is a builder with its own connections,
+// e.g. NewBuilderWriterReader for a writer and reader pair
+type ShardedBuilder struct {
+	shards    []Builder
+	shardFunc ShardFunc
+}
+
+func NewShardedBuilder(shardFunc ShardFunc, shards ...Builder) ShardedBuilder {
+	return ShardedBuilder{
+		shards:    shards,
+		shardFunc: shardFunc,
+	}
+}
+
+func (s ShardedBuilder) Len() int {
+	return len(s.shards)
+}
+
+// Shard returns new builder of the shard by index
+func (s ShardedBuilder) Shard(i int) (Builder, error) {
+	if i < 0 || i >= len(s.shards) {
+		return Builder{}, fmt.Errorf("%w: index %d of %d shards", ErrShardNotFound, i, len(s.shards))
+	}
+	return s.shards[i].New(), nil
+}
+
+// ForShard returns new builder of the shard of the key
+func (s ShardedBuilder) ForShard(key any) (Builder, error) {
+	i, err := s.shardFunc(key)
+	if err != nil {
+		return Builder{}, err
+	}
+	return s.Shard(i)
+}
+
+// ForContext returns new builder of the shard of the key from ContextWithShardKey
+func (s ShardedBuilder) ForContext(ctx context.Context) (Builder, error) {
+	key := ctx.Value(shardContextKey{})
+	if key == nil {
+		return Builder{}, ErrShardKeyNotSet
+	}
+	return s.ForShard(key)
+}
+
+// GetAllContext runs select query on all shards concurrently and merges rows into dest, a pointer to slice.
+// Merged rows are sorted by less of dest indexes when it is not nil, then limit and offset of the query are applied
+// to merged rows, every shard returns up to limit plus offset rows. Limit and offset require less.
+// The first failed shard cancels queries of the other shards.
+func (s ShardedBuilder) GetAllContext(ctx context.Context, query Builder, dest any, less func(i, j int) bool) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.Elem().Kind() != reflect.Slice {
+		return ErrRowsNotSlice
+	}
+	sliceType := destValue.Elem().Type()
+
+	limit, offset := query.limit, query.offset
+	if less == nil && (limit > 0 || offset > 0) {
+		return ErrShardOrderNotSet
+	}
+	shardQuery := query
+	if limit > 0 {
+		shardQuery.limit = limit + offset
+	}
+	shardQuery.offset = 0
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]reflect.Value, len(s.shards))
+	var firstErr error
+	var errOnce sync.Once
+
+	var wg sync.WaitGroup
+	for i := range s.shards {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rows := reflect.New(sliceType)
+			if err := shardQuery.withConnections(s.shards[i]).GetAllContext(ctx, rows.Interface()); err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("shard %d: %w", i, err)
+					cancel()
+				})
+				return
+			}
+			results[i] = rows.Elem()
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	merged := reflect.MakeSlice(sliceType, 0, 0)
+	for i := range s.shards {
+		merged = reflect.AppendSlice(merged, results[i])
+	}
+	destValue.Elem().Set(merged)
+
+	if less != nil {
+		sort.SliceStable(destValue.Elem().Interface(), less)
+	}
+
+	if offset > 0 || limit > 0 {
+		start := min(int(offset), merged.Len())
+		end := merged.Len()
+		if limit > 0 {
+			end = min(start+int(limit), end)
+		}
+		destValue.Elem().Set(destValue.Elem().Slice(start, end))
+	}
+
+	return nil
+}
+
+// withConnections returns the builder with connections and dialect of the shard
+func (b Builder) withConnections(shard Builder) Builder {
+	b.writerConn = shard.writerConn
+	b.readerConn = shard.readerConn
+	b.readerPool = shard.readerPool
+	b.dialect = shard.dialect
+	return b
+}
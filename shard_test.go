@@ -0,0 +1,108 @@
+package ondatra
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardFunc(t *testing.T) {
+	hash := HashShard(4)
+	first, err := hash(int64(42))
+	require.NoError(t, err)
+	second, err := hash(int64(42))
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Less(t, first, 4)
+
+	ranges := RangeShard(100, 200)
+	for key, expect := range map[any]int{int64(5): 0, 100: 1, uint(199): 1, int32(1000): 2} {
+		shard, err := ranges(key)
+		assert.NoError(t, err)
+		assert.Equal(t, expect, shard)
+	}
+	_, err = ranges("a")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+
+	lookup := LookupShard(map[string]int{"acme": 1})
+	shard, err := lookup("acme")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, shard)
+	_, err = lookup("other")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+}
+
+func TestShardedBuilder(t *testing.T) {
+	shard0, mock0 := newMockBuilder(t)
+	shard1, mock1 := newMockBuilder(t)
+	sharded := NewShardedBuilder(RangeShard(1000), shard0, shard1)
+	assert.Equal(t, 2, sharded.Len())
+
+	mock1.ExpectExec("INSERT INTO events (account_id, name) VALUES ($1,$2)").
+		WithArgs(1500, "login").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock0.ExpectQuery("SELECT name FROM events WHERE account_id = $1").
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("logout"))
+
+	b, err := sharded.ForShard(1500)
+	require.NoError(t, err)
+	_, err = b.Insert().Into("events").Columns("account_id", "name").Values(1500, "login").ExecContext(context.Background())
+	assert.NoError(t, err)
+
+	ctx := ContextWithShardKey(context.Background(), 7)
+	b, err = sharded.ForContext(ctx)
+	require.NoError(t, err)
+	var name string
+	assert.NoError(t, b.Select("name").From("events").Where("account_id = ?", 7).GetContext(ctx, &name))
+	assert.Equal(t, "logout", name)
+
+	_, err = sharded.ForContext(context.Background())
+	assert.ErrorIs(t, err, ErrShardKeyNotSet)
+	_, err = NewShardedBuilder(LookupShard(map[string]int{"a": 5}), shard0).ForShard("a")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+}
+
+func TestShardedBuilder_GetAllContext(t *testing.T) {
+	shard0, mock0 := newMockBuilder(t)
+	shard1, mock1 := newMockBuilder(t)
+	sharded := NewShardedBuilder(HashShard(2), shard0, shard1)
+
+	mock0.ExpectQuery("SELECT id FROM events WHERE kind = $1 ORDER BY id LIMIT 3").
+		WithArgs("login").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(4).AddRow(5))
+	mock1.ExpectQuery("SELECT id FROM events WHERE kind = $1 ORDER BY id LIMIT 3").
+		WithArgs("login").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(3))
+
+	var ids []int64
+	query := NewEmptyBuilder().Select("id").From("events").Where("kind = ?", "login").OrderBy("id").LimitOffset(2, 1)
+	err := sharded.GetAllContext(context.Background(), query, &ids, func(i, j int) bool { return ids[i] < ids[j] })
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, ids)
+
+	err = sharded.GetAllContext(context.Background(), NewEmptyBuilder().Select("id").From("events").Limit(2), &ids, nil)
+	assert.ErrorIs(t, err, ErrShardOrderNotSet)
+}
+
+func TestShardedBuilder_GetAllContextCancel(t *testing.T) {
+	shard0, mock0 := newMockBuilder(t)
+	db, mock1, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	sharded := NewShardedBuilder(HashShard(2), shard0, NewBuilder(sqlx.NewDb(db, "postgres")))
+
+	mock0.ExpectQuery("SELECT id FROM events").WillReturnError(assert.AnError)
+	mock1.ExpectQuery("SELECT id FROM events").WillDelayFor(time.Minute).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var ids []int64
+	start := time.Now()
+	err = sharded.GetAllContext(context.Background(), NewEmptyBuilder().Select("id").From("events"), &ids, nil)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Less(t, time.Since(start), time.Minute)
+}
@@ -0,0 +1,382 @@
+package ondatra
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// StmtCacheStats are metrics of StmtCache
+type StmtCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+}
+
+// HitRate returns share of executions which reused prepared statement
+func (s StmtCacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+func (s StmtCacheStats) add(other StmtCacheStats) StmtCacheStats {
+	return StmtCacheStats{
+		Hits:      s.Hits + other.Hits,
+		Misses:    s.Misses + other.Misses,
+		Evictions: s.Evictions + other.Evictions,
+		Size:      s.Size + other.Size,
+	}
+}
+
+// StmtCache is a bounded LRU of prepared statements keyed by connection and SQL, evicted statements are closed
+// when they are not used anymore. It is safe for concurrent use, a statement is prepared once per cache entry,
+// though database/sql may prepare it again on each pooled connection it runs on.
+// The cache may be shared by builders and connections, its statements stay prepared until Close.
+type StmtCache struct {
+	mu       sync.Mutex
+	capacity int
+	lru      *list.List
+	entries  map[stmtKey]*list.Element
+	stats    StmtCacheStats
+}
+
+type stmtKey struct {
+	conn  any
+	query string
+}
+
+type stmtEntry struct {
+	key     stmtKey
+	stmt    *sqlx.Stmt
+	err     error
+	ready   chan struct{}
+	refs    int
+	evicted bool
+}
+
+type prepareFunc func(ctx context.Context, query string) (*sqlx.Stmt, error)
+
+func NewStmtCache(capacity int) *StmtCache {
+	return &StmtCache{
+		capacity: max(1, capacity),
+		lru:      list.New(),
+		entries:  make(map[stmtKey]*list.Element),
+	}
+}
+
+func (c *StmtCache) Stats() StmtCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = c.lru.Len()
+	return stats
+}
+
+// Close removes all statements from the cache and closes them
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	var closing []*stmtEntry
+	for c.lru.Len() > 0 {
+		if e := c.removeOldest(); e != nil {
+			closing = append(closing, e)
+		}
+	}
+	c.mu.Unlock()
+
+	return closeEntries(closing)
+}
+
+// use runs exec with prepared statement of the query of conn, the statement is prepared on the first use
+func (c *StmtCache) use(ctx context.Context, key stmtKey, prepare prepareFunc, exec func(stmt *sqlx.Stmt) error) error {
+	e, err := c.acquire(ctx, key, prepare)
+	defer c.release(e)
+
+	if err != nil {
+		return err
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return exec(e.stmt)
+}
+
+// acquire returns entry of the statement, the statement is prepared without cancellation of ctx,
+// so a canceled first caller does not fail callers which wait for the same statement
+func (c *StmtCache) acquire(ctx context.Context, key stmtKey, prepare prepareFunc) (*stmtEntry, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*stmtEntry)
+		e.refs++
+		c.lru.MoveToFront(elem)
+		c.stats.Hits++
+		c.mu.Unlock()
+
+		select {
+		case <-e.ready:
+			return e, nil
+		case <-ctx.Done():
+			return e, ctx.Err()
+		}
+	}
+
+	e := &stmtEntry{
+		key:   key,
+		ready: make(chan struct{}),
+		refs:  1,
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	c.stats.Misses++
+
+	var closing []*stmtEntry
+	for c.lru.Len() > c.capacity {
+		if evicted := c.removeOldest(); evicted != nil {
+			closing = append(closing, evicted)
+		}
+		c.stats.Evictions++
+	}
+	c.mu.Unlock()
+
+	_ = closeEntries(closing)
+
+	e.stmt, e.err = prepare(context.WithoutCancel(ctx), key.query)
+	close(e.ready)
+
+	if e.err != nil {
+		c.mu.Lock()
+		if elem, ok := c.entries[key]; ok && elem.Value == e {
+			c.lru.Remove(elem)
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+	return e, nil
+}
+
+func (c *StmtCache) release(e *stmtEntry) {
+	c.mu.Lock()
+	e.refs--
+	closing := e.refs == 0 && e.evicted
+	c.mu.Unlock()
+
+	if closing {
+		_ = closeEntries([]*stmtEntry{e})
+	}
+}
+
+// removeOldest removes the least recently used statement, it is returned if it can be closed now
+func (c *StmtCache) removeOldest() *stmtEntry {
+	elem := c.lru.Back()
+	e := elem.Value.(*stmtEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, e.key)
+
+	e.evicted = true
+	if e.refs > 0 {
+		return nil
+	}
+	return e
+}
+
+func closeEntries(entries []*stmtEntry) error {
+	var err error
+	for _, e := range entries {
+		<-e.ready
+		if e.stmt == nil {
+			continue
+		}
+		if closeErr := e.stmt.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// cachedConn executes queries by prepared statements of the cache
+type cachedConn struct {
+	cache   *StmtCache
+	prepare prepareFunc
+	conn    interface {
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	}
+}
+
+func (c cachedConn) Get(dest any, query string, args ...any) error {
+	return c.GetContext(context.Background(), dest, query, args...)
+}
+
+func (c cachedConn) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return c.cache.use(ctx, stmtKey{conn: c.conn, query: query}, c.prepare, func(stmt *sqlx.Stmt) error {
+		return stmt.GetContext(ctx, dest, args...)
+	})
+}
+
+func (c cachedConn) Select(dest any, query string, args ...any) error {
+	return c.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (c cachedConn) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return c.cache.use(ctx, stmtKey{conn: c.conn, query: query}, c.prepare, func(stmt *sqlx.Stmt) error {
+		return stmt.SelectContext(ctx, dest, args...)
+	})
+}
+
+func (c cachedConn) Exec(query string, args ...any) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+func (c cachedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var result sql.Result
+	err := c.cache.use(ctx, stmtKey{conn: c.conn, query: query}, c.prepare, func(stmt *sqlx.Stmt) error {
+		var err error
+		result, err = stmt.ExecContext(ctx, args...)
+		return err
+	})
+	return result, err
+}
+
+func (c cachedConn) Query(query string, args ...any) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext returns rows of prepared statement, eviction of the statement does not close open rows
+func (c cachedConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := c.cache.use(ctx, stmtKey{conn: c.conn, query: query}, c.prepare, func(stmt *sqlx.Stmt) error {
+		var err error
+		rows, err = stmt.QueryContext(ctx, args...)
+		return err
+	})
+	return rows, err
+}
+
+func (c cachedConn) QueryRow(query string, args ...any) *sql.Row {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext returns row of prepared statement, if the statement can not be prepared the query is sent as is
+// so the error is returned by Row.Scan
+func (c cachedConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	var row *sql.Row
+	err := c.cache.use(ctx, stmtKey{conn: c.conn, query: query}, c.prepare, func(stmt *sqlx.Stmt) error {
+		row = stmt.QueryRowContext(ctx, args...)
+		return nil
+	})
+	if err != nil {
+		return c.conn.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
+
+// CachedDB is a Connection which reuses prepared statements of identical queries
+type CachedDB struct {
+	cachedConn
+	db *DB
+}
+
+// NewCachedDB returns connection with statement cache of capacity, transactions of RunInTransaction
+// get their own cache of the same capacity
+func NewCachedDB(db *sqlx.DB, capacity int) *CachedDB {
+	return NewCachedDBWithCache(db, NewStmtCache(capacity))
+}
+
+// NewCachedDBWithCache returns connection with shared statement cache
+func NewCachedDBWithCache(db *sqlx.DB, cache *StmtCache) *CachedDB {
+	return &CachedDB{
+		cachedConn: cachedConn{
+			cache:   cache,
+			prepare: db.PreparexContext,
+			conn:    db,
+		},
+		db: &DB{DB: db},
+	}
+}
+
+func (c *CachedDB) Rebind(query string) string {
+	return c.db.Rebind(query)
+}
+
+func (c *CachedDB) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return c.db.BeginTx(ctx)
+}
+
+func (c *CachedDB) Stats() StmtCacheStats {
+	return c.cache.Stats()
+}
+
+// Close closes all statements of the cache, including statements of other connections sharing it,
+// the database stays open
+func (c *CachedDB) Close() error {
+	return c.cache.Close()
+}
+
+// newTx returns transaction connection with its own statement cache
+func (c *CachedDB) newTx(tx *sqlx.Tx) *CachedTx {
+	return &CachedTx{
+		cachedConn: cachedConn{
+			cache:   NewStmtCache(c.cache.capacity),
+			prepare: tx.PreparexContext,
+			conn:    tx,
+		},
+		tx: &Tx{Tx: tx, driver: c.db.Driver()},
+	}
+}
+
+// CachedTx is a transaction Connection which reuses prepared statements of identical queries,
+// database/sql closes statements of the transaction when it is committed or rolled back
+// and the cache of the transaction is dropped with it
+type CachedTx struct {
+	cachedConn
+	tx *Tx
+}
+
+func (c *CachedTx) Rebind(query string) string {
+	return c.tx.Rebind(query)
+}
+
+func (c *CachedTx) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	return c.tx.BeginTx(ctx)
+}
+
+func (c *CachedTx) Stats() StmtCacheStats {
+	return c.cache.Stats()
+}
+
+// StmtCache enables the statement cache for connections of the builder. The cache is shared by builders
+// and connections which use it, it is created once and closed by StmtCache.Close when it is not used anymore.
+func (b Builder) StmtCache(cache *StmtCache) Builder {
+	if db, ok := b.writerConn.(*DB); ok {
+		b.writerConn = NewCachedDBWithCache(db.DB, cache)
+	}
+	if db, ok := b.readerConn.(*DB); ok {
+		b.readerConn = NewCachedDBWithCache(db.DB, cache)
+	}
+	return b
+}
+
+// StmtCacheStats returns sum of metrics of statement caches of the builder connections
+func (b Builder) StmtCacheStats() StmtCacheStats {
+	var stats StmtCacheStats
+	var caches []*StmtCache
+	for _, conn := range []Connection{b.writerConn, b.readerConn} {
+		var cache *StmtCache
+		switch c := conn.(type) {
+		case *CachedDB:
+			cache = c.cache
+		case *CachedTx:
+			cache = c.cache
+		}
+		if cache != nil && !slices.Contains(caches, cache) {
+			caches = append(caches, cache)
+			stats = stats.add(cache.Stats())
+		}
+	}
+	return stats
+}
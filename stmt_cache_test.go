@@ -0,0 +1,116 @@
+package ondatra
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStmtCache(t *testing.T) {
+	b, mock := newMockBuilder(t)
+	b = b.StmtCache(NewStmtCache(1))
+
+	selectUser := "SELECT name FROM users WHERE id = $1"
+	deleteUser := "DELETE FROM users WHERE id = $1"
+
+	prepareSelect := mock.ExpectPrepare(selectUser).WillBeClosed()
+	prepareSelect.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	prepareSelect.ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("b"))
+	mock.ExpectPrepare(deleteUser).ExpectExec().WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := context.Background()
+	var name string
+	assert.NoError(t, b.Select("name").From("users").Where("id = ?", 1).GetContext(ctx, &name))
+	assert.Equal(t, "a", name)
+	assert.NoError(t, b.Select("name").From("users").Where("id = ?", 2).GetContext(ctx, &name))
+	assert.Equal(t, "b", name)
+	_, err := b.Delete().From("users").Where("id = ?", 3).ExecContext(ctx)
+	assert.NoError(t, err)
+
+	stats := b.StmtCacheStats()
+	assert.Equal(t, StmtCacheStats{Hits: 1, Misses: 2, Evictions: 1, Size: 1}, stats)
+	assert.InDelta(t, 1.0/3, stats.HitRate(), 0.001)
+}
+
+func TestStmtCache_Concurrent(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+	// database/sql prepares the statement again on every pooled connection
+	db.SetMaxOpenConns(1)
+	b := NewBuilder(sqlx.NewDb(db, "postgres")).StmtCache(NewStmtCache(8))
+	mock.MatchExpectationsInOrder(false)
+
+	const goroutines = 20
+	prepare := mock.ExpectPrepare("SELECT name FROM users WHERE id = $1")
+	for i := 0; i < goroutines; i++ {
+		prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	}
+
+	query := b.Select("name").From("users").Where("id = ?", 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var name string
+			assert.NoError(t, query.GetContext(context.Background(), &name))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, StmtCacheStats{Hits: goroutines - 1, Misses: 1, Size: 1}, b.StmtCacheStats())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStmtCache_Transaction(t *testing.T) {
+	b, mock := newMockBuilder(t)
+	b = b.StmtCache(NewStmtCache(4))
+
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare("UPDATE users SET name = $1 WHERE id = $2")
+	prepare.ExpectExec().WithArgs("a", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	prepare.ExpectExec().WithArgs("b", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := b.RunInTransaction(context.Background(), func(tx Builder) error {
+		for i, name := range []string{"a", "b"} {
+			if _, err := tx.Update().Table("users").Set("name", name).Where("id = ?", i+1).ExecContext(context.Background()); err != nil {
+				return err
+			}
+		}
+		assert.Equal(t, StmtCacheStats{Hits: 1, Misses: 1, Size: 1}, tx.StmtCacheStats())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, StmtCacheStats{}, b.StmtCacheStats())
+}
+
+func TestStmtCache_Shared(t *testing.T) {
+	b, mock := newMockBuilder(t)
+	cache := NewStmtCache(4)
+	first, second := b.StmtCache(cache), b.StmtCache(cache)
+
+	prepare := mock.ExpectPrepare("SELECT name FROM users WHERE id = $1").WillBeClosed()
+	prepare.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	prepare.ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("b"))
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	var name string
+	assert.ErrorIs(t, first.Select("name").From("users").Where("id = ?", 1).GetContext(canceled, &name), context.Canceled)
+	assert.NoError(t, second.Select("name").From("users").Where("id = ?", 1).GetContext(context.Background(), &name))
+	assert.Equal(t, "a", name)
+	assert.NoError(t, second.Select("name").From("users").Where("id = ?", 2).GetContext(context.Background(), &name))
+	assert.Equal(t, "b", name)
+
+	assert.Equal(t, StmtCacheStats{Hits: 2, Misses: 1, Size: 1}, first.StmtCacheStats())
+	assert.NoError(t, cache.Close())
+	assert.Equal(t, 0, second.StmtCacheStats().Size)
+}
@@ -0,0 +1,45 @@
+package ondatra
+
+import "fmt"
+
+// Subquery wraps the builder into parentheses, use it as scalar subquery in any Expr
+func Subquery(b Builder) Expr {
+	return NewExpr("(?)", b)
+}
+
+func Exists(b Builder) Expr {
+	return NewExpr("EXISTS (?)", b)
+}
+
+func NotExists(b Builder) Expr {
+	return NewExpr("NOT EXISTS (?)", b)
+}
+
+// JoinSelect join subquery with alias, arguments of query go before arguments of on condition.
+// Cross join has no condition, its on and arguments are ignored.
+func (b Builder) JoinSelect(joinType string, query Builder, alias, on string, args ...any) Builder {
+	return b.JoinRaw(joinSubquerySQL(joinType, "", alias, on), joinSubqueryArgs(joinType, query, args)...)
+}
+
+// JoinLateral join LATERAL subquery with alias, the subquery can reference columns of preceding tables.
+// Empty on condition is rendered as ON true, cross join has no condition, its on and arguments are ignored.
+func (b Builder) JoinLateral(joinType string, query Builder, alias, on string, args ...any) Builder {
+	return b.JoinRaw(joinSubquerySQL(joinType, "LATERAL ", alias, on), joinSubqueryArgs(joinType, query, args)...)
+}
+
+func joinSubquerySQL(joinType, lateral, alias, on string) string {
+	if joinType == JoinCross {
+		return fmt.Sprintf("%s JOIN %s(?) AS %s", joinType, lateral, alias)
+	}
+	if on == "" {
+		on = "true"
+	}
+	return fmt.Sprintf("%s JOIN %s(?) AS %s ON %s", joinType, lateral, alias, on)
+}
+
+func joinSubqueryArgs(joinType string, query Builder, args []any) []any {
+	if joinType == JoinCross {
+		return []any{query}
+	}
+	return append([]any{query}, args...)
+}
@@ -0,0 +1,96 @@
+package ondatra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSubquery(t *testing.T) {
+	orderID := NewColumn[int64]("orders", "id")
+	orderTotal := NewColumn[int64]("orders", "total")
+	userID := NewColumn[int64]("users", "id")
+
+	paidOrders := NewEmptyBuilder().
+		Select("user_id").
+		From("payments").
+		Where("status = ?", "paid").
+		PlaceholderFormat(Dollar)
+
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+	}{
+		{
+			name: "exists",
+			builder: NewEmptyBuilder().
+				Select("id").
+				From("users").
+				Where("active = ?", true).
+				WhereExpr(
+					Exists(NewEmptyBuilder().Select("1").From("orders").Where("orders.user_id = users.id AND total > ?", 10)),
+					NotExists(NewEmptyBuilder().Select("1").From("bans").Where("bans.user_id = users.id AND kind = ?", "hard")),
+				).
+				PlaceholderFormat(Dollar),
+			expectQuery: "SELECT id FROM users WHERE active = $1 " +
+				"AND EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND total > $2) " +
+				"AND NOT EXISTS (SELECT 1 FROM bans WHERE bans.user_id = users.id AND kind = $3)",
+			expectArgs: []any{true, 10, "hard"},
+		}, {
+			name: "in query",
+			builder: NewEmptyBuilder().
+				Select("id").
+				From("users").
+				WhereExpr(
+					userID.InQuery(paidOrders),
+					userID.NotInQuery(NewEmptyBuilder().Select("user_id").From("bans")),
+					userID.NEQ.Value(5),
+				).
+				PlaceholderFormat(Dollar),
+			expectQuery: "SELECT id FROM users WHERE \"users\".id IN (SELECT user_id FROM payments WHERE status = $1) " +
+				"AND \"users\".id NOT IN (SELECT user_id FROM bans) AND \"users\".id != $2",
+			expectArgs: []any{"paid", int64(5)},
+		}, {
+			name: "scalar query",
+			builder: NewEmptyBuilder().
+				Update().
+				Table("orders").
+				SetExpr(orderTotal.Set.Query(NewEmptyBuilder().Select("SUM(amount)").From("items").Where("order_id = ?", 1))).
+				WhereExpr(
+					orderTotal.GT.Query(NewEmptyBuilder().Select("AVG(total)").From("orders").Where("status = ?", "paid")),
+					orderID.EQ.Value(1),
+				),
+			expectQuery: "UPDATE orders SET total = (SELECT SUM(amount) FROM items WHERE order_id = ?) " +
+				"WHERE \"orders\".total > (SELECT AVG(total) FROM orders WHERE status = ?) AND \"orders\".id = ?",
+			expectArgs: []any{1, "paid", int64(1)},
+		}, {
+			name: "join lateral",
+			builder: NewEmptyBuilder().
+				Select("u.id", "o.total").
+				From("users u").
+				JoinLateral(JoinLeft,
+					NewEmptyBuilder().Select("total").From("orders").Where("orders.user_id = u.id AND total > ?", 1).Limit(3),
+					"o", "o.total < ?", 100,
+				).
+				JoinLateral(JoinCross, NewEmptyBuilder().Select("count(*) AS c").From("logins").Where("user_id = u.id"), "l", "l.c > ?", 5).
+				JoinSelect(JoinInner, NewEmptyBuilder().Select("user_id").From("admins").Where("level = ?", 2), "a", "a.user_id = u.id").
+				Where("u.id = ?", 3),
+			expectQuery: "SELECT u.id, o.total FROM users u " +
+				"LEFT JOIN LATERAL (SELECT total FROM orders WHERE orders.user_id = u.id AND total > ? LIMIT 3) AS o ON o.total < ? " +
+				"CROSS JOIN LATERAL (SELECT count(*) AS c FROM logins WHERE user_id = u.id) AS l " +
+				"INNER JOIN (SELECT user_id FROM admins WHERE level = ?) AS a ON a.user_id = u.id " +
+				"WHERE u.id = ?",
+			expectArgs: []any{1, 100, 2, 3},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
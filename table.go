@@ -1,10 +1,14 @@
 package ondatra
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Table struct {
-	name    string
-	columns []string
+	name         string
+	columns      []string
+	tenantColumn string
 }
 
 func NewTable(name string, columns []string) Table {
@@ -14,6 +18,12 @@ func NewTable(name string, columns []string) Table {
 	}
 }
 
+// TenantColumn marks table as tenant scoped by the column for TenantGuard
+func (t Table) TenantColumn(column string) Table {
+	t.tenantColumn = column
+	return t
+}
+
 func (t Table) Name() string {
 	return t.name
 }
@@ -41,3 +51,156 @@ func (t Table) ColumnsAlias(alias string) []string {
 	}
 	return columns
 }
+
+// tableRef is a table of FROM list or join set by name, e.g. public.users u
+type tableRef struct {
+	// name is the table name as it is written, it is empty for subqueries
+	name string
+	// alias is alias of the table or its name
+	alias string
+	// joined is true for tables which follow JOIN, other tables start FROM list or follow comma
+	joined bool
+}
+
+// tableAliasWords end a table reference, they are not aliases
+var tableAliasWords = map[string]bool{
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "CROSS": true, "NATURAL": true,
+	"OUTER": true, "ON": true, "USING": true, "WHERE": true, "SET": true, "WITH": true, "LATERAL": true,
+}
+
+// parseTableRefs returns tables of FROM list or join SQL, e.g. "users u, countries" or "LEFT JOIN users u ON ...".
+// Every table of the list is returned, subqueries by (?) placeholder have no name.
+// It returns false when a table is parenthesised raw SQL which can not be parsed, e.g. (users) or (SELECT ...).
+func parseTableRefs(rawSQL string) ([]tableRef, bool) {
+	tokens := tableTokens(rawSQL)
+
+	var refs []tableRef
+	depth := 0
+	// table position is the start of FROM list, the token after comma or after JOIN
+	position := len(tokens) > 0 && !isJoinKeyword(tokens[0])
+	joined := false
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if !position || depth > 0 {
+			switch {
+			case token == "(":
+				depth++
+			case token == ")":
+				depth--
+			case depth == 0 && token == ",":
+				position, joined = true, false
+			case depth == 0 && strings.EqualFold(token, "JOIN"):
+				position, joined = true, true
+			}
+			continue
+		}
+
+		position = false
+		if strings.EqualFold(token, "LATERAL") || strings.EqualFold(token, "ONLY") {
+			position = true
+			continue
+		}
+
+		ref := tableRef{name: token, joined: joined}
+		if token == "(" {
+			if i+2 >= len(tokens) || tokens[i+1] != "?" || tokens[i+2] != ")" {
+				return refs, false
+			}
+			ref.name = ""
+			i += 2
+		}
+		ref.alias = ref.name
+
+		if i+2 < len(tokens) && strings.EqualFold(tokens[i+1], "AS") {
+			ref.alias = tokens[i+2]
+			i += 2
+		} else if i+1 < len(tokens) && tokens[i+1] != "," && tokens[i+1] != "(" &&
+			!tableAliasWords[strings.ToUpper(tokens[i+1])] {
+			ref.alias = tokens[i+1]
+			i++
+		}
+		refs = append(refs, ref)
+	}
+	return refs, true
+}
+
+// tableTokens splits SQL into words, commas and parentheses, quoted identifiers are single words
+func tableTokens(rawSQL string) []string {
+	var tokens []string
+	for i := 0; i < len(rawSQL); {
+		c := rawSQL[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == ',' || c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			start := i
+			for i < len(rawSQL) && !strings.ContainsRune(" \t\n\r,()", rune(rawSQL[i])) {
+				if closing := quoteClosing(rawSQL[i]); closing != 0 {
+					if end := strings.IndexByte(rawSQL[i+1:], closing); end >= 0 {
+						i += end + 1
+					}
+				}
+				i++
+			}
+			tokens = append(tokens, rawSQL[start:i])
+		}
+	}
+	return tokens
+}
+
+func quoteClosing(c byte) byte {
+	switch c {
+	case '"', '`':
+		return c
+	case '[':
+		return ']'
+	default:
+		return 0
+	}
+}
+
+// tableSQL returns SQL of table or join expression without arguments, subqueries are left as placeholders
+func tableSQL(table Expr) string {
+	switch e := table.(type) {
+	case nil:
+		return ""
+	case identExpr:
+		return e.ident
+	case expr:
+		return e.rawSQL
+	default:
+		rawSQL, _, err := table.ToSQL()
+		if err != nil {
+			return ""
+		}
+		return rawSQL
+	}
+}
+
+func isJoinKeyword(word string) bool {
+	switch strings.ToUpper(word) {
+	case "JOIN", "INNER", "LEFT", "RIGHT", "FULL", "CROSS", "NATURAL", ",":
+		return true
+	default:
+		return false
+	}
+}
+
+// unqualifiedTable returns table name without schema and quotes, e.g. users of "public"."users"
+// tableAlias returns alias of the first table of table SQL or its name, e.g. u of "users AS u"
+// and public.users of "public.users"
+func tableAlias(rawSQL string) string {
+	refs, _ := parseTableRefs(rawSQL)
+	if len(refs) == 0 {
+		return ""
+	}
+	return refs[0].alias
+}
+
+func unqualifiedTable(name string) string {
+	name = name[strings.LastIndex(name, ".")+1:]
+	return strings.Trim(name, "\"`[]")
+}
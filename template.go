@@ -0,0 +1,112 @@
+package ondatra
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+)
+
+// Template is a compiled query, its SQL is built once and arguments are bound on every execution
+type Template struct {
+	builder Builder
+	query   string
+	numArgs int
+	named   NamedPlaceholderFormat
+}
+
+// Compile freezes SQL of the builder into Template, every argument of the builder is a parameter slot of
+// Template.Bind in order of ToSQL arguments. Number of slots is fixed by the builder arguments, e.g. IN list
+// of 50 ids is compiled to 50 slots and is bound only with 50 ids, lists of other length need another Template.
+// Builders with tenant guard or policies can not be compiled because their conditions depend on context.
+func (b Builder) Compile() (Template, error) {
+	if b.tenantGuard != nil || b.policies != nil {
+		return Template{}, ErrTemplateNotStatic
+	}
+
+	var err error
+	var query string
+	var args []any
+	if b.writerConn != nil || b.readerConn != nil {
+		query, args, err = b.ToQueryWithArgs()
+	} else {
+		query, args, err = b.ToSQL()
+	}
+	if err != nil {
+		return Template{}, err
+	}
+
+	named, _ := b.placeholderFormat.(NamedPlaceholderFormat)
+	return Template{
+		builder: b,
+		query:   query,
+		numArgs: len(args),
+		named:   named,
+	}, nil
+}
+
+func (t Template) Query() string {
+	return t.query
+}
+
+func (t Template) NumArgs() int {
+	return t.numArgs
+}
+
+// Bind returns query of the template with arguments, number of arguments must be equal to NumArgs
+func (t Template) Bind(args ...any) (string, []any, error) {
+	if len(args) != t.numArgs {
+		return "", nil, fmt.Errorf("%w: expected %d, got %d", ErrTemplateArgs, t.numArgs, len(args))
+	}
+
+	bound := slices.Clone(args)
+	if t.named != nil {
+		bound = t.named.NamedArgs(bound)
+	}
+
+	if DebugMode {
+		log.Println("Query:", t.query, "Arguments:", bound)
+	}
+
+	return t.query, bound, nil
+}
+
+func (t Template) bind(args ...any) (string, []any, error) {
+	if t.builder.writerConn == nil && t.builder.readerConn == nil {
+		return "", nil, SqlDBNotSet
+	}
+	return t.Bind(args...)
+}
+
+func (t Template) GetContext(ctx context.Context, dest any, args ...any) error {
+	query, bound, err := t.bind(args...)
+	if err != nil {
+		return err
+	}
+	return t.builder.connContext(ctx).GetContext(ctx, dest, query, bound...)
+}
+
+func (t Template) GetAllContext(ctx context.Context, dest any, args ...any) error {
+	query, bound, err := t.bind(args...)
+	if err != nil {
+		return err
+	}
+	return t.builder.connContext(ctx).SelectContext(ctx, dest, query, bound...)
+}
+
+func (t Template) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	query, bound, err := t.bind(args...)
+	if err != nil {
+		return nil, err
+	}
+	return t.builder.connContext(ctx).ExecContext(ctx, query, bound...)
+}
+
+func (t Template) QueryContext(ctx context.Context, args ...any) (*sql.Rows, error) {
+	query, bound, err := t.bind(args...)
+	if err != nil {
+		return nil, err
+	}
+	return t.builder.connContext(ctx).QueryContext(ctx, query, bound...)
+}
@@ -0,0 +1,101 @@
+package ondatra
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate(t *testing.T) {
+	template, err := NewEmptyBuilder().
+		Select("id").
+		From("users").
+		Where("status = ?", "").
+		WhereExpr(NewExpr("created_at > ? + ?", 0, NewExpr("?", 0))).
+		Compile()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE status = ? AND created_at > ? + ?", template.Query())
+	assert.Equal(t, 3, template.NumArgs())
+
+	query, args, err := template.Bind("active", 10, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE status = ? AND created_at > ? + ?", query)
+	assert.Equal(t, []any{"active", 10, 20}, args)
+
+	_, _, err = template.Bind("active")
+	assert.ErrorIs(t, err, ErrTemplateArgs)
+
+	named, err := NewEmptyBuilder().Select("id").From("users").Where("id = ?", 0).PlaceholderFormat(NamedAt).Compile()
+	assert.NoError(t, err)
+	query, args, err = named.Bind(5)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id = @arg1", query)
+	assert.Equal(t, []any{sql.Named("arg1", 5)}, args)
+
+	_, err = NewEmptyBuilder().Policies(NewPolicyRegistry()).Select("id").From("users").Compile()
+	assert.ErrorIs(t, err, ErrTemplateNotStatic)
+}
+
+func TestTemplate_Exec(t *testing.T) {
+	b, mock := newMockBuilder(t)
+
+	template, err := b.Select("name").From("users").Where("id = ?", 0).Compile()
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("SELECT name FROM users WHERE id = $1").WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+
+	var name string
+	assert.NoError(t, template.GetContext(context.Background(), &name, 7))
+	assert.Equal(t, "a", name)
+}
+
+func benchmarkBuilder() Builder {
+	users := NewTable("users", []string{"id", "name", "email"})
+	orders := NewTable("orders", []string{"id", "user_id", "amount"})
+	items := NewTable("items", []string{"id", "order_id", "price"})
+
+	join := NewJoinBuilder("users").NewJoin(JoinLeft, orders, "orders", "user_id", "id")
+	ids := make([]int64, 50)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+
+	return NewEmptyBuilder().
+		Select(users.ColumnsTable()...).
+		From("users").
+		JoinExpr(join, join.NewJoin(JoinLeft, items, "items", "order_id", "id")).
+		WhereExpr(NewColumn[int64]("users", "id").IN(ids...)).
+		Where("users.status = ?", "active").
+		OrderBy("users.id DESC").
+		Limit(20)
+}
+
+func BenchmarkBuilder_ToSQL(b *testing.B) {
+	builder := benchmarkBuilder()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := builder.ToSQL(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTemplate_Bind(b *testing.B) {
+	template, err := benchmarkBuilder().Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	args := make([]any, template.NumArgs())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := template.Bind(args...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
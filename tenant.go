@@ -0,0 +1,244 @@
+package ondatra
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// TenantColumn is the default column of tenant identifier
+const TenantColumn = "tenant_id"
+
+const modelTagTenant = "tenant"
+
+type tenantContextKey struct{}
+
+// ContextWithTenant returns context with tenant identifier, builder methods with context use it
+// when the tenant is not set by Builder.Tenant
+func ContextWithTenant(ctx context.Context, tenantID any) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+func TenantFromContext(ctx context.Context) (any, bool) {
+	tenantID := ctx.Value(tenantContextKey{})
+	return tenantID, tenantID != nil
+}
+
+// TenantError is returned when a statement uses a tenant scoped table without a tenant
+// or by raw SQL the guard can not scope, then Reason is set
+type TenantError struct {
+	Table   string
+	Command string
+	Reason  string
+}
+
+func (e TenantError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("%s: %s of table %s %s", ErrTenantNotSet, e.Command, e.Table, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s of table %s", ErrTenantNotSet, e.Command, e.Table)
+}
+
+func (e TenantError) Unwrap() error {
+	return ErrTenantNotSet
+}
+
+// TenantGuard keeps tenant scoped tables and their tenant columns
+type TenantGuard struct {
+	mu     sync.RWMutex
+	tables map[string]string
+}
+
+func NewTenantGuard() *TenantGuard {
+	return &TenantGuard{
+		tables: make(map[string]string),
+	}
+}
+
+// Register opts in tables, tenant column is set by Table.TenantColumn or TenantColumn by default
+func (g *TenantGuard) Register(tables ...Table) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, table := range tables {
+		column := table.tenantColumn
+		if column == "" {
+			column = TenantColumn
+		}
+		g.tables[unqualifiedTable(table.name)] = column
+	}
+}
+
+// RegisterModel opts in table when model has a field tagged as tenant, e.g. `db:"tenant_id,column,tenant"`
+func (g *TenantGuard) RegisterModel(table string, model any) {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		dbTags := strings.Split(t.Field(i).Tag.Get("db"), ",")
+		if slices.Contains(dbTags, modelTagTenant) {
+			g.Register(NewTable(table, nil).TenantColumn(dbTags[0]))
+			return
+		}
+	}
+}
+
+// Column returns tenant column of the table, ok is false when the table is not tenant scoped.
+// Schema of the table is ignored, e.g. public.users is users.
+func (g *TenantGuard) Column(table string) (string, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	column, ok := g.tables[unqualifiedTable(table)]
+	return column, ok
+}
+
+func (g *TenantGuard) tableColumn(table Table) (string, bool) {
+	if table.tenantColumn != "" {
+		return table.tenantColumn, true
+	}
+	return g.Column(table.name)
+}
+
+// TenantGuard enables injection of tenant predicate to statements of tenant scoped tables
+func (b Builder) TenantGuard(guard *TenantGuard) Builder {
+	b.tenantGuard = guard
+	return b
+}
+
+// Tenant set tenant identifier, it has priority over tenant of context
+func (b Builder) Tenant(tenantID any) Builder {
+	b.tenantID = tenantID
+	return b
+}
+
+func (b Builder) tenantFromContext(ctx context.Context) Builder {
+	if b.tenantID == nil {
+		b.tenantID, _ = TenantFromContext(ctx)
+	}
+	return b
+}
+
+// applyTenant adds table.tenant_id = ? to where of select, update and delete,
+// to ON of join expressions and to columns of insert. Subqueries get the guard and the tenant,
+// raw joins of tenant scoped tables are refused.
+func (b Builder) applyTenant() (Builder, error) {
+	if b.tenantGuard == nil {
+		return b, nil
+	}
+	b = b.bindTenant()
+
+	tables := []Expr{b.table}
+	if b.command == CommandUpdate || b.command == CommandDelete {
+		tables = append(tables, b.from...)
+	}
+
+	var conditions []Expr
+	for i, table := range tables {
+		if table == nil {
+			continue
+		}
+		refs, ok := parseTableRefs(tableSQL(table))
+		if !ok {
+			return b, TenantError{Table: tableSQL(table), Command: b.command, Reason: "parenthesised raw SQL can not be scoped"}
+		}
+		for j, ref := range refs {
+			column, ok := b.tenantGuard.Column(ref.name)
+			if !ok || ref.name == "" {
+				continue
+			}
+			name := unqualifiedTable(ref.name)
+			if ref.joined {
+				return b, TenantError{Table: name, Command: b.command, Reason: "joined by raw SQL can not be scoped"}
+			}
+			if b.tenantID == nil {
+				return b, TenantError{Table: name, Command: b.command}
+			}
+
+			if i == 0 && j == 0 && b.command == CommandInsert {
+				b = b.insertTenant(column)
+				continue
+			}
+			conditions = append(conditions, NewExpr(fmt.Sprintf("%s.%s = ?", ref.alias, column), b.tenantID))
+		}
+	}
+	b = b.guardWhere(conditions...)
+
+	b.joins = slices.Clone(b.joins)
+	for i, join := range b.joins {
+		aliasJoin, conditions, ok := asAliasJoin(join)
+		if !ok {
+			if err := b.tenantGuard.rawJoin(b.command, tableSQL(join)); err != nil {
+				return b, err
+			}
+			continue
+		}
+		column, ok := b.tenantGuard.tableColumn(aliasJoin.table)
+		if !ok {
+			continue
+		}
+		if b.tenantID == nil {
+			return b, TenantError{Table: aliasJoin.table.name, Command: b.command}
+		}
+		condition := NewExpr(fmt.Sprintf("\"%s\".%s = ?", aliasJoin.alias, column), b.tenantID)
+		b.joins[i] = guardedJoin{join: aliasJoin, conditions: append(slices.Clone(conditions), condition)}
+	}
+
+	return b, nil
+}
+
+// bindTenant passes the guard and the tenant to subqueries, the guard and the tenant of a subquery are kept
+func (b Builder) bindTenant() Builder {
+	guard, tenantID := b.tenantGuard, b.tenantID
+	return b.bindParts(func(subquery Builder) Builder {
+		if subquery.tenantGuard == nil {
+			subquery.tenantGuard = guard
+		}
+		if subquery.tenantID == nil {
+			subquery.tenantID = tenantID
+		}
+		return subquery
+	})
+}
+
+// rawJoin returns error if raw join SQL joins a tenant scoped table or can not be parsed
+func (g *TenantGuard) rawJoin(command, rawSQL string) error {
+	refs, ok := parseTableRefs(rawSQL)
+	if !ok {
+		return TenantError{Table: rawSQL, Command: command, Reason: "parenthesised raw SQL can not be scoped"}
+	}
+	for _, ref := range refs {
+		if _, ok := g.Column(ref.name); ok && ref.name != "" {
+			return TenantError{Table: unqualifiedTable(ref.name), Command: command, Reason: "joined by raw SQL can not be scoped"}
+		}
+	}
+	return nil
+}
+
+// insertTenant adds tenant column to insert or overwrites its values by the tenant
+func (b Builder) insertTenant(column string) Builder {
+	index := slices.Index(b.columns, column)
+	if index < 0 {
+		b.columns = append(slices.Clone(b.columns), column)
+	}
+
+	insertValues := make([][]any, len(b.insertValues))
+	for i, values := range b.insertValues {
+		insertValues[i] = slices.Clone(values)
+		if index < 0 {
+			insertValues[i] = append(insertValues[i], b.tenantID)
+		} else if index < len(values) {
+			insertValues[i][index] = b.tenantID
+		}
+	}
+	b.insertValues = insertValues
+	return b
+}
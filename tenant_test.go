@@ -0,0 +1,178 @@
+package ondatra
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantGuard(t *testing.T) {
+	type Order struct {
+		ID       int64  `db:"id,column,pk"`
+		TenantID int64  `db:"org_id,column,tenant"`
+		Status   string `db:"status,column"`
+	}
+
+	guard := NewTenantGuard()
+	guard.Register(NewTable("users", nil))
+	guard.RegisterModel("orders", []Order{})
+
+	items := NewTable("items", []string{"id"}).TenantColumn("tenant_id")
+	builder := NewEmptyBuilder().TenantGuard(guard).Tenant(7)
+
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+		expectErr   error
+	}{
+		{
+			name:        "select",
+			builder:     builder.Select("id").From("users").Where("active = ?", true),
+			expectQuery: "SELECT id FROM users WHERE (active = ?) AND users.tenant_id = ?",
+			expectArgs:  []any{true, 7},
+		}, {
+			name:        "select alias",
+			builder:     builder.Select("u.id").From("users AS u"),
+			expectQuery: "SELECT u.id FROM users AS u WHERE u.tenant_id = ?",
+			expectArgs:  []any{7},
+		}, {
+			name:        "not scoped table",
+			builder:     builder.Select("id").From("countries"),
+			expectQuery: "SELECT id FROM countries",
+		}, {
+			name: "join expr",
+			builder: builder.Select("id").From("countries").
+				JoinExpr(NewJoinBuilder("countries").NewJoin(JoinLeft, items, "i", "country_id", "id")),
+			expectQuery: "SELECT id, \"i\".id AS \"i.id\" FROM countries " +
+				"LEFT JOIN items as \"i\" ON \"i\".country_id = \"countries\".\"id\" AND \"i\".tenant_id = ?",
+			expectArgs: []any{7},
+		}, {
+			name:        "update",
+			builder:     builder.Update().Table("orders").Set("status", "paid").Where("id = ?", 1),
+			expectQuery: "UPDATE orders SET status = ? WHERE (id = ?) AND orders.org_id = ?",
+			expectArgs:  []any{"paid", 1, 7},
+		}, {
+			name:        "update from",
+			builder:     builder.Update().Table("orders").UpdateFrom("users u").Set("status", "paid").Where("orders.user_id = u.id"),
+			expectQuery: "UPDATE orders SET status = ? FROM users u WHERE (orders.user_id = u.id) AND orders.org_id = ? AND u.tenant_id = ?",
+			expectArgs:  []any{"paid", 7, 7},
+		}, {
+			name:        "delete",
+			builder:     builder.Delete().From("users").Where("id = ?", 1),
+			expectQuery: "DELETE FROM users WHERE (id = ?) AND users.tenant_id = ?",
+			expectArgs:  []any{1, 7},
+		}, {
+			name:        "insert",
+			builder:     builder.Insert().Into("users").Columns("name").Values("a").Values("b"),
+			expectQuery: "INSERT INTO users (name, tenant_id) VALUES (?,?),(?,?)",
+			expectArgs:  []any{"a", 7, "b", 7},
+		}, {
+			name:        "insert overwrites tenant",
+			builder:     builder.Insert().Into("orders").StructColumns(Order{ID: 1, TenantID: 3, Status: "new"}),
+			expectQuery: "INSERT INTO orders (id, org_id, status) VALUES (?,?,?)",
+			expectArgs:  []any{int64(1), 7, "new"},
+		}, {
+			name:        "where with or",
+			builder:     builder.Select("id").From("users").Where("a = ? OR b = ?", 1, 2).Where("c = ?", 3),
+			expectQuery: "SELECT id FROM users WHERE (a = ? OR b = ? AND c = ?) AND users.tenant_id = ?",
+			expectArgs:  []any{1, 2, 3, 7},
+		}, {
+			name:        "schema",
+			builder:     builder.Select("id").From("public.users"),
+			expectQuery: "SELECT id FROM public.users WHERE public.users.tenant_id = ?",
+			expectArgs:  []any{7},
+		}, {
+			name: "subquery",
+			builder: builder.Select("id").From("countries").
+				WhereExpr(Exists(NewEmptyBuilder().Select("1").From("users u").Where("u.country_id = countries.id"))).
+				WhereExpr(OR(NewExpr("id IN (?)", NewEmptyBuilder().Select("country_id").From("orders")), NewExpr("id = ?", 0))),
+			expectQuery: "SELECT id FROM countries WHERE EXISTS (SELECT 1 FROM users u WHERE (u.country_id = countries.id) AND u.tenant_id = ?) " +
+				"AND (id IN (SELECT country_id FROM orders WHERE orders.org_id = ?) OR id = ?)",
+			expectArgs: []any{7, 7, 0},
+		}, {
+			name:      "raw join",
+			builder:   builder.Select("id").From("countries c").Join(JoinLeft, "users u ON u.country_id = c.id"),
+			expectErr: ErrTenantNotSet,
+		}, {
+			name:      "raw join in table",
+			builder:   builder.Select("id").From("countries c JOIN public.users u ON u.country_id = c.id"),
+			expectErr: ErrTenantNotSet,
+		}, {
+			name:        "comma list",
+			builder:     builder.Select("id").From("users, countries"),
+			expectQuery: "SELECT id FROM users, countries WHERE users.tenant_id = ?",
+			expectArgs:  []any{7},
+		}, {
+			name:        "comma list without spaces",
+			builder:     builder.Select("id").From("countries c,users u,orders"),
+			expectQuery: "SELECT id FROM countries c,users u,orders WHERE u.tenant_id = ? AND orders.org_id = ?",
+			expectArgs:  []any{7, 7},
+		}, {
+			name:        "subquery placeholder",
+			builder:     builder.Select("id").FromSelect(NewEmptyBuilder().Select("id").From("users"), "s"),
+			expectQuery: "SELECT id FROM (SELECT id FROM users WHERE users.tenant_id = ?) AS s",
+			expectArgs:  []any{7},
+		}, {
+			name:      "comma list without tenant",
+			builder:   builder.Tenant(nil).Select("id").From("countries, users"),
+			expectErr: ErrTenantNotSet,
+		}, {
+			name:      "parenthesised raw join",
+			builder:   builder.Select("id").From("countries").JoinRaw("LEFT JOIN (users) ON true"),
+			expectErr: ErrTenantNotSet,
+		}, {
+			name:      "parenthesised table",
+			builder:   builder.Select("id").From("(users) u"),
+			expectErr: ErrTenantNotSet,
+		}, {
+			name:      "without tenant",
+			builder:   builder.Tenant(nil).Select("id").From("users"),
+			expectErr: ErrTenantNotSet,
+		}, {
+			name: "join without tenant",
+			builder: builder.Tenant(nil).Select("id").From("countries").
+				JoinExpr(NewJoinBuilder("countries").NewJoin(JoinLeft, items, "i", "country_id", "id")),
+			expectErr: ErrTenantNotSet,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			if test.expectErr != nil {
+				assert.ErrorIs(t, err, test.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
+
+func TestTenantFromContext(t *testing.T) {
+	guard := NewTenantGuard()
+	guard.Register(NewTable("users", nil))
+
+	b, mock := newMockBuilder(t)
+	b = b.TenantGuard(guard)
+
+	_, _, err := b.Select("id").From("users").ToQueryWithArgs()
+	var tenantErr TenantError
+	assert.True(t, errors.As(err, &tenantErr))
+	assert.Equal(t, "users", tenantErr.Table)
+
+	mock.ExpectExec("DELETE FROM users WHERE (id = $1) AND users.tenant_id = $2").
+		WithArgs(1, 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := ContextWithTenant(context.Background(), 9)
+	_, err = b.Delete().From("users").Where("id = ?", 1).ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
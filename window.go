@@ -0,0 +1,199 @@
+package ondatra
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	FrameUnboundedPreceding FrameBound = "UNBOUNDED PRECEDING"
+	FrameCurrentRow         FrameBound = "CURRENT ROW"
+	FrameUnboundedFollowing FrameBound = "UNBOUNDED FOLLOWING"
+)
+
+// FrameBound is a start or end of window frame
+type FrameBound string
+
+func FramePreceding(offset int64) FrameBound {
+	return FrameBound(fmt.Sprintf("%d PRECEDING", offset))
+}
+
+func FrameFollowing(offset int64) FrameBound {
+	return FrameBound(fmt.Sprintf("%d FOLLOWING", offset))
+}
+
+// WindowSpec is a window definition used in OVER (...) and WINDOW name AS (...)
+type WindowSpec struct {
+	base        string
+	partitionBy []Expr
+	orderBy     []Expr
+	frame       string
+}
+
+func NewWindow() WindowSpec {
+	return WindowSpec{}
+}
+
+// Base extends existing named window, e.g. OVER (w ORDER BY ...)
+func (w WindowSpec) Base(name string) WindowSpec {
+	w.base = name
+	return w
+}
+
+func (w WindowSpec) PartitionBy(columns ...string) WindowSpec {
+	for _, column := range columns {
+		w.partitionBy = append(w.partitionBy, NewExpr(column))
+	}
+	return w
+}
+
+func (w WindowSpec) PartitionByExpr(expr ...Expr) WindowSpec {
+	for i := range expr {
+		if expr[i] != nil {
+			w.partitionBy = append(w.partitionBy, expr[i])
+		}
+	}
+	return w
+}
+
+func (w WindowSpec) OrderBy(orderBys ...string) WindowSpec {
+	for _, orderBy := range orderBys {
+		w.orderBy = append(w.orderBy, NewExpr(orderBy))
+	}
+	return w
+}
+
+func (w WindowSpec) OrderByArgs(rawSQL string, args ...any) WindowSpec {
+	w.orderBy = append(w.orderBy, NewExpr(rawSQL, args...))
+	return w
+}
+
+// Rows set frame ROWS BETWEEN start AND end
+func (w WindowSpec) Rows(start, end FrameBound) WindowSpec {
+	w.frame = fmt.Sprintf("ROWS BETWEEN %s AND %s", start, end)
+	return w
+}
+
+// Range set frame RANGE BETWEEN start AND end
+func (w WindowSpec) Range(start, end FrameBound) WindowSpec {
+	w.frame = fmt.Sprintf("RANGE BETWEEN %s AND %s", start, end)
+	return w
+}
+
+// ToSQL returns window definition without parentheses
+func (w WindowSpec) ToSQL() (string, []any, error) {
+	var err error
+	var args []any
+	var parts []string
+	var buffer strings.Builder
+
+	if w.base != "" {
+		parts = append(parts, w.base)
+	}
+
+	if len(w.partitionBy) > 0 {
+		buffer.WriteString("PARTITION BY ")
+		if args, err = writeExprs(w.partitionBy, &buffer, ", ", args); err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, buffer.String())
+		buffer.Reset()
+	}
+
+	if len(w.orderBy) > 0 {
+		buffer.WriteString("ORDER BY ")
+		if args, err = writeExprs(w.orderBy, &buffer, ", ", args); err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, buffer.String())
+	}
+
+	if w.frame != "" {
+		parts = append(parts, w.frame)
+	}
+
+	return strings.Join(parts, " "), args, nil
+}
+
+func (w WindowSpec) bindSubqueries(bind func(Builder) Builder) Expr {
+	w.partitionBy = bindSubqueriesExprs(w.partitionBy, bind)
+	w.orderBy = bindSubqueriesExprs(w.orderBy, bind)
+	return w
+}
+
+// WindowFunc is a function evaluated over window, it is Expr without OVER clause
+type WindowFunc struct {
+	Expr
+}
+
+func NewWindowFunc(rawSQL string, args ...any) WindowFunc {
+	return WindowFunc{Expr: NewExpr(rawSQL, args...)}
+}
+
+func (f WindowFunc) bindSubqueries(bind func(Builder) Builder) Expr {
+	f.Expr = bindSubqueries(f.Expr, bind)
+	return f
+}
+
+// Over returns function with inline window definition, e.g. ROW_NUMBER() OVER (PARTITION BY ...)
+func (f WindowFunc) Over(w WindowSpec) Expr {
+	return NewExpr("? OVER (?)", f.Expr, w)
+}
+
+// OverName returns function with window defined by Builder.Window, e.g. ROW_NUMBER() OVER w
+func (f WindowFunc) OverName(name string) Expr {
+	return NewExpr("? OVER "+name, f.Expr)
+}
+
+func RowNumber() WindowFunc {
+	return NewWindowFunc("ROW_NUMBER()")
+}
+
+func Rank() WindowFunc {
+	return NewWindowFunc("RANK()")
+}
+
+func DenseRank() WindowFunc {
+	return NewWindowFunc("DENSE_RANK()")
+}
+
+func PercentRank() WindowFunc {
+	return NewWindowFunc("PERCENT_RANK()")
+}
+
+func CumeDist() WindowFunc {
+	return NewWindowFunc("CUME_DIST()")
+}
+
+func NTile(buckets int64) WindowFunc {
+	return NewWindowFunc(fmt.Sprintf("NTILE(%d)", buckets))
+}
+
+// Lag returns value of column offset rows before the current row or defaultValue if it is not nil
+func Lag(column string, offset int64, defaultValue any) WindowFunc {
+	return offsetWindowFunc("LAG", column, offset, defaultValue)
+}
+
+// Lead returns value of column offset rows after the current row or defaultValue if it is not nil
+func Lead(column string, offset int64, defaultValue any) WindowFunc {
+	return offsetWindowFunc("LEAD", column, offset, defaultValue)
+}
+
+func FirstValue(column string) WindowFunc {
+	return NewWindowFunc(fmt.Sprintf("FIRST_VALUE(%s)", column))
+}
+
+func LastValue(column string) WindowFunc {
+	return NewWindowFunc(fmt.Sprintf("LAST_VALUE(%s)", column))
+}
+
+func NthValue(column string, n int64) WindowFunc {
+	return NewWindowFunc(fmt.Sprintf("NTH_VALUE(%s, %d)", column, n))
+}
+
+func offsetWindowFunc(function, column string, offset int64, defaultValue any) WindowFunc {
+	if defaultValue == nil {
+		return NewWindowFunc(fmt.Sprintf("%s(%s, %d)", function, column, offset))
+	}
+	return NewWindowFunc(fmt.Sprintf("%s(%s, %d, ?)", function, column, offset), defaultValue)
+}
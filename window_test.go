@@ -0,0 +1,63 @@
+package ondatra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWindow(t *testing.T) {
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+	}{
+		{
+			name: "inline window",
+			builder: NewEmptyBuilder().
+				Select("id").
+				SelectColumn("? AS rn", RowNumber().Over(NewWindow().PartitionBy("user_id").OrderBy("created_at DESC"))).
+				SelectColumn("? AS prev", Lag("amount", 1, 0).Over(NewWindow().OrderBy("created_at"))).
+				SelectColumn("? AS running", NewWindowFunc("SUM(amount) FILTER (WHERE amount > ?)", 5).Over(
+					NewWindow().
+						PartitionByExpr(NewExpr("date_trunc(?, created_at)", "day")).
+						OrderByArgs("amount > ? DESC", 10).
+						Rows(FramePreceding(3), FrameCurrentRow),
+				)).
+				From("orders").
+				Where("amount > ?", 1).
+				OrderByArgs("? DESC", Rank().Over(NewWindow().OrderBy("amount"))),
+			expectQuery: "SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at DESC) AS rn, " +
+				"LAG(amount, 1, ?) OVER (ORDER BY created_at) AS prev, " +
+				"SUM(amount) FILTER (WHERE amount > ?) OVER (PARTITION BY date_trunc(?, created_at) " +
+				"ORDER BY amount > ? DESC ROWS BETWEEN 3 PRECEDING AND CURRENT ROW) AS running " +
+				"FROM orders WHERE amount > ? ORDER BY RANK() OVER (ORDER BY amount) DESC",
+			expectArgs: []any{0, 5, "day", 10, 1},
+		}, {
+			name: "named window",
+			builder: NewEmptyBuilder().
+				Select("id").
+				SelectColumn("? AS rn", RowNumber().OverName("w")).
+				SelectColumn("? AS total", NewWindowFunc("SUM(amount)").Over(
+					NewWindow().Base("w").Range(FrameUnboundedPreceding, FrameUnboundedFollowing),
+				)).
+				From("orders").
+				Where("user_id = ?", 1).
+				Window("w", NewWindow().PartitionBy("user_id").OrderByArgs("amount > ?", 2)).
+				Limit(10),
+			expectQuery: "SELECT id, ROW_NUMBER() OVER w AS rn, " +
+				"SUM(amount) OVER (w RANGE BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) AS total " +
+				"FROM orders WHERE user_id = ? WINDOW w AS (PARTITION BY user_id ORDER BY amount > ?) LIMIT 10",
+			expectArgs: []any{1, 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package ondatra
+
+import (
+	"io"
+	"strings"
+)
+
+// UpdateFrom add tables to UPDATE ... FROM, joins of the update are joined to these tables
+func (b Builder) UpdateFrom(tables ...string) Builder {
+	for _, table := range tables {
+		b.from = append(b.from, identExpr{kind: identTable, ident: table})
+	}
+	return b
+}
+
+// UpdateFromSelect add subquery with alias to UPDATE ... FROM
+func (b Builder) UpdateFromSelect(from Expr, alias string) Builder {
+	b.from = append(b.from, NewExpr("(?) AS "+alias, from))
+	return b
+}
+
+// DeleteUsing add tables to DELETE ... USING, joins of the delete are joined to these tables
+func (b Builder) DeleteUsing(tables ...string) Builder {
+	return b.UpdateFrom(tables...)
+}
+
+// DeleteUsingSelect add subquery with alias to DELETE ... USING
+func (b Builder) DeleteUsingSelect(from Expr, alias string) Builder {
+	return b.UpdateFromSelect(from, alias)
+}
+
+// writeUpdate writes table and SET of update:
+// mysql UPDATE a, b JOIN c ON ... SET ...
+// sqlserver UPDATE a SET ... FROM a, b JOIN c ON ...
+// postgres and others UPDATE a SET ... FROM b JOIN c ON ...
+func (b Builder) writeUpdate(w *strings.Builder, args []any) ([]any, error) {
+	var err error
+	multiTable := len(b.from) > 0 || len(b.joins) > 0
+
+	switch {
+	case b.dialect == DialectMySQL && multiTable:
+		if args, err = b.writeTables(w, args); err != nil {
+			return nil, err
+		}
+		if args, err = b.writeSet(w, args); err != nil {
+			return nil, err
+		}
+	case b.dialect == DialectSQLServer && multiTable:
+		w.WriteString(tableAlias(tableSQL(b.table)))
+		if args, err = b.writeSet(w, args); err != nil {
+			return nil, err
+		}
+		w.WriteString("FROM ")
+		if args, err = b.writeTables(w, args); err != nil {
+			return nil, err
+		}
+		w.WriteString(" ")
+	default:
+		if len(b.joins) > 0 && len(b.from) == 0 {
+			return nil, ErrJoinWithoutFrom
+		}
+		if b.table != nil {
+			if args, err = writeExpr(b.table, w, args); err != nil {
+				return nil, err
+			}
+		}
+		if args, err = b.writeSet(w, args); err != nil {
+			return nil, err
+		}
+		if len(b.from) > 0 {
+			w.WriteString("FROM ")
+			if args, err = b.writeFrom(w, args); err != nil {
+				return nil, err
+			}
+			w.WriteString(" ")
+		}
+	}
+
+	return args, nil
+}
+
+// writeDelete writes table of delete:
+// mysql and sqlserver DELETE a FROM a, b JOIN c ON ...
+// postgres and others DELETE FROM a USING b JOIN c ON ...
+func (b Builder) writeDelete(w *strings.Builder, args []any) ([]any, error) {
+	var err error
+	multiTable := len(b.from) > 0 || len(b.joins) > 0
+
+	if (b.dialect == DialectMySQL || b.dialect == DialectSQLServer) && multiTable {
+		w.WriteString(tableAlias(tableSQL(b.table)))
+		w.WriteString(" FROM ")
+		if args, err = b.writeTables(w, args); err != nil {
+			return nil, err
+		}
+		w.WriteString(" ")
+		return args, nil
+	}
+
+	if len(b.joins) > 0 && len(b.from) == 0 {
+		return nil, ErrJoinWithoutFrom
+	}
+
+	if b.table != nil {
+		w.WriteString("FROM ")
+		if args, err = writeExpr(b.table, w, args); err != nil {
+			return nil, err
+		}
+		w.WriteString(" ")
+	}
+
+	if len(b.from) > 0 {
+		w.WriteString("USING ")
+		if args, err = b.writeFrom(w, args); err != nil {
+			return nil, err
+		}
+		w.WriteString(" ")
+	}
+
+	return args, nil
+}
+
+func (b Builder) writeSet(w io.Writer, args []any) ([]any, error) {
+	var err error
+	if _, err = io.WriteString(w, " SET "); err != nil {
+		return nil, err
+	}
+	if args, err = writeExprs(b.updateValues, w, ", ", args); err != nil {
+		return nil, err
+	}
+	if _, err = io.WriteString(w, " "); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// writeTables writes the table, additional tables and joins
+func (b Builder) writeTables(w io.Writer, args []any) ([]any, error) {
+	var err error
+	if b.table != nil {
+		if args, err = writeExpr(b.table, w, args); err != nil {
+			return nil, err
+		}
+	}
+	for i := range b.from {
+		if _, err = io.WriteString(w, ", "); err != nil {
+			return nil, err
+		}
+		if args, err = writeExpr(b.from[i], w, args); err != nil {
+			return nil, err
+		}
+	}
+	for i := range b.joins {
+		if _, err = io.WriteString(w, " "); err != nil {
+			return nil, err
+		}
+		if args, err = writeExpr(b.joins[i], w, args); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// writeFrom writes additional tables and joins
+func (b Builder) writeFrom(w io.Writer, args []any) ([]any, error) {
+	var err error
+	if args, err = writeExprs(b.from, w, ", ", args); err != nil {
+		return nil, err
+	}
+	for i := range b.joins {
+		if _, err = io.WriteString(w, " "); err != nil {
+			return nil, err
+		}
+		if args, err = writeExpr(b.joins[i], w, args); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
@@ -0,0 +1,125 @@
+package ondatra
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuilder_UpdateFrom(t *testing.T) {
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+	}{
+		{
+			name: "postgres",
+			builder: NewEmptyBuilder().
+				Dialect(DialectPostgres).
+				Update().
+				Table("orders").
+				Set("status", "paid").
+				UpdateFrom("payments").
+				Join(JoinLeft, "accounts ON accounts.id = payments.account_id AND accounts.kind = ?", "main").
+				Where("payments.order_id = orders.id AND payments.amount > ?", 10),
+			expectQuery: "UPDATE orders SET status = ? FROM payments " +
+				"LEFT JOIN accounts ON accounts.id = payments.account_id AND accounts.kind = ? " +
+				"WHERE payments.order_id = orders.id AND payments.amount > ?",
+			expectArgs: []any{"paid", "main", 10},
+		}, {
+			name: "postgres from select",
+			builder: NewEmptyBuilder().
+				Update().
+				Table("orders").
+				Set("total", NewExpr("t.total")).
+				UpdateFromSelect(NewEmptyBuilder().Select("order_id", "SUM(amount) AS total").From("items").Where("amount > ?", 0).GroupBy("order_id"), "t").
+				Where("t.order_id = orders.id"),
+			expectQuery: "UPDATE orders SET total = t.total " +
+				"FROM (SELECT order_id, SUM(amount) AS total FROM items WHERE amount > ? GROUP BY order_id) AS t " +
+				"WHERE t.order_id = orders.id",
+			expectArgs: []any{0},
+		}, {
+			name: "mysql",
+			builder: NewEmptyBuilder().
+				Dialect(DialectMySQL).
+				Update().
+				Table("orders o").
+				Join(JoinInner, "payments p ON p.order_id = o.id AND p.amount > ?", 10).
+				Set("o.status", "paid").
+				Where("o.id = ?", 1),
+			expectQuery: "UPDATE orders o INNER JOIN payments p ON p.order_id = o.id AND p.amount > ? " +
+				"SET o.status = ? WHERE o.id = ?",
+			expectArgs: []any{10, "paid", 1},
+		}, {
+			name: "sqlserver",
+			builder: NewEmptyBuilder().
+				Dialect(DialectSQLServer).
+				Update().
+				Table("orders o").
+				Join(JoinInner, "payments p ON p.order_id = o.id").
+				Set("o.status", "paid"),
+			expectQuery: "UPDATE o SET o.status = ? FROM orders o INNER JOIN payments p ON p.order_id = o.id",
+			expectArgs:  []any{"paid"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
+
+func TestBuilder_DeleteUsing(t *testing.T) {
+	var tests = []struct {
+		name        string
+		builder     Builder
+		expectQuery string
+		expectArgs  []any
+	}{
+		{
+			name: "postgres",
+			builder: NewEmptyBuilder().
+				Dialect(DialectPostgres).
+				Delete().
+				From("sessions").
+				DeleteUsing("users").
+				JoinExpr(NewJoinBuilder("users").NewJoin(JoinLeft, NewTable("bans", []string{"id"}), "ban", "user_id", "id")).
+				Where("sessions.user_id = users.id AND users.active = ?", false),
+			expectQuery: "DELETE FROM sessions USING users " +
+				"LEFT JOIN bans as \"ban\" ON \"ban\".user_id = \"users\".\"id\" " +
+				"WHERE sessions.user_id = users.id AND users.active = ?",
+			expectArgs: []any{false},
+		}, {
+			name: "mysql",
+			builder: NewEmptyBuilder().
+				Dialect(DialectMySQL).
+				Delete().
+				From("sessions AS s").
+				Join(JoinInner, "users u ON u.id = s.user_id").
+				Where("u.active = ?", false),
+			expectQuery: "DELETE s FROM sessions AS s INNER JOIN users u ON u.id = s.user_id WHERE u.active = ?",
+			expectArgs:  []any{false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, args, err := test.builder.ToSQL()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectQuery, query)
+			assert.Equal(t, test.expectArgs, args)
+		})
+	}
+}
+
+func TestBuilder_JoinWithoutFrom(t *testing.T) {
+	_, _, err := NewEmptyBuilder().Dialect(DialectPostgres).Delete().From("a").Join(JoinLeft, "b ON b.id = a.b_id").ToSQL()
+	assert.ErrorIs(t, err, ErrJoinWithoutFrom)
+
+	_, _, err = NewEmptyBuilder().Update().Table("a").Set("x", 1).Join(JoinLeft, "b ON b.id = a.b_id").ToSQL()
+	assert.ErrorIs(t, err, ErrJoinWithoutFrom)
+}